@@ -0,0 +1,125 @@
+// Command grpc-server serves the SimpleStorage contract over gRPC.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/digidny/simple-storage-dapp/backend/internal/envfile"
+	"github.com/digidny/simple-storage-dapp/backend/internal/grpcapi"
+	"github.com/digidny/simple-storage-dapp/backend/internal/logging"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout bounds how long in-flight calls get to finish.  Open
+// WatchValue streams never finish on their own and are cut off after it.
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	envFiles := flag.String("env-file", "", "comma-separated dotenv files to load, earlier ones taking precedence (default ENV_FILE or .env)")
+	network := flag.String("network", "", "network to use from the networks file (default NETWORK)")
+	networksPath := flag.String("networks", "", "path to the networks file (default NETWORKS_FILE or networks.json)")
+	configFile := flag.String("config", "", "JSON file of settings, below the environment in precedence (default CONFIG_FILE)")
+	listenAddr := flag.String("listen", "", "address to listen on (default GRPC_LISTEN_ADDR or :9090)")
+	settings := make(client.SettingFlags)
+	flag.Var(settings, "set", "set a setting by its environment variable name, as `NAME=value`, overriding every other source; repeatable")
+	flag.Parse()
+
+	logger, err := logging.FromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	layers := client.Layers{
+		Flags:        settings,
+		NetworksPath: *networksPath,
+		Network:      *network,
+		FilePath:     *configFile,
+	}
+	if err := run(ctx, logger, *envFiles, layers, *listenAddr); err != nil {
+		stop()
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until ctx is cancelled, then shuts down gracefully.  Settings
+// resolve as in the CLI: the first of layers.Flags, the environment, the
+// network entry and the config file to set one wins.
+func run(ctx context.Context, logger *slog.Logger, envFiles string, layers client.Layers, listenAddr string) error {
+	if err := envfile.Load(logger, envfile.Paths(envFiles)); err != nil {
+		return err
+	}
+	if layers.Network == "" {
+		layers.Network = os.Getenv("NETWORK")
+	}
+	if layers.NetworksPath == "" {
+		layers.NetworksPath = os.Getenv("NETWORKS_FILE")
+	}
+	if layers.NetworksPath == "" {
+		layers.NetworksPath = "networks.json"
+	}
+	if layers.FilePath == "" {
+		layers.FilePath = os.Getenv("CONFIG_FILE")
+	}
+	if listenAddr == "" {
+		listenAddr = os.Getenv("GRPC_LISTEN_ADDR")
+	}
+	if listenAddr == "" {
+		listenAddr = ":9090"
+	}
+
+	cfg, _, err := layers.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	cfg.Logger = logger
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	lis, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+	srv := grpc.NewServer()
+	grpcapi.NewServer(storageClient).Register(srv)
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(lis) }()
+	logger.Info("serving contract", "address", storageClient.Address(), "listen", lis.Addr().String())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Info("shutting down")
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		srv.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Command server serves the SimpleStorage contract over HTTP.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/digidny/simple-storage-dapp/backend/internal/envfile"
+	"github.com/digidny/simple-storage-dapp/backend/internal/httpapi"
+	"github.com/digidny/simple-storage-dapp/backend/internal/logging"
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// shutdownTimeout bounds how long in-flight requests get to finish.
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	envFiles := flag.String("env-file", "", "comma-separated dotenv files to load, earlier ones taking precedence (default ENV_FILE or .env)")
+	network := flag.String("network", "", "network to use from the networks file (default NETWORK)")
+	networksPath := flag.String("networks", "", "path to the networks file (default NETWORKS_FILE or networks.json)")
+	configFile := flag.String("config", "", "JSON file of settings, below the environment in precedence (default CONFIG_FILE)")
+	listenAddr := flag.String("listen", "", "address to listen on (default LISTEN_ADDR or :8080)")
+	settings := make(client.SettingFlags)
+	flag.Var(settings, "set", "set a setting by its environment variable name, as `NAME=value`, overriding every other source; repeatable")
+	flag.Parse()
+
+	logger, err := logging.FromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	layers := client.Layers{
+		Flags:        settings,
+		NetworksPath: *networksPath,
+		Network:      *network,
+		FilePath:     *configFile,
+	}
+	if err := run(ctx, logger, *envFiles, layers, *listenAddr); err != nil {
+		stop()
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until ctx is cancelled, then shuts down gracefully.  Settings
+// resolve as in the CLI: the first of layers.Flags, the environment, the
+// network entry and the config file to set one wins.
+func run(ctx context.Context, logger *slog.Logger, envFiles string, layers client.Layers, listenAddr string) error {
+	if err := envfile.Load(logger, envfile.Paths(envFiles)); err != nil {
+		return err
+	}
+	if layers.Network == "" {
+		layers.Network = os.Getenv("NETWORK")
+	}
+	if layers.NetworksPath == "" {
+		layers.NetworksPath = os.Getenv("NETWORKS_FILE")
+	}
+	if layers.NetworksPath == "" {
+		layers.NetworksPath = "networks.json"
+	}
+	if layers.FilePath == "" {
+		layers.FilePath = os.Getenv("CONFIG_FILE")
+	}
+	if listenAddr == "" {
+		listenAddr = os.Getenv("LISTEN_ADDR")
+	}
+	if listenAddr == "" {
+		listenAddr = ":8080"
+	}
+
+	cfg, _, err := layers.Load()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	cfg.Logger = logger
+	cfg.Metrics = metrics.New(prometheus.NewRegistry())
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	srv := &http.Server{
+		Addr:    listenAddr,
+		Handler: httpapi.NewServer(storageClient, cfg.Metrics),
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	logger.Info("serving contract", "address", storageClient.Address(), "listen", listenAddr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
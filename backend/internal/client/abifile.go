@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+)
+
+// loadABI reads the JSON contract ABI at path, as written by solc --abi,
+// and adds the generated binding's methods, events and errors that it
+// does not declare, so Get, Set and the other typed methods keep working
+// against a modified contract.
+func loadABI(path string, generated *abi.ABI) (*abi.ABI, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading abi: %w", err)
+	}
+	defer f.Close()
+	parsed, err := abi.JSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing abi %s: %w", path, err)
+	}
+	if parsed.Methods == nil {
+		parsed.Methods = make(map[string]abi.Method)
+	}
+	if parsed.Events == nil {
+		parsed.Events = make(map[string]abi.Event)
+	}
+	if parsed.Errors == nil {
+		parsed.Errors = make(map[string]abi.Error)
+	}
+	for name, m := range generated.Methods {
+		if _, ok := parsed.Methods[name]; !ok {
+			parsed.Methods[name] = m
+		}
+	}
+	for name, ev := range generated.Events {
+		if _, ok := parsed.Events[name]; !ok {
+			parsed.Events[name] = ev
+		}
+	}
+	for name, e := range generated.Errors {
+		if _, ok := parsed.Errors[name]; !ok {
+			parsed.Errors[name] = e
+		}
+	}
+	return &parsed, nil
+}
+
+// ParseArgs converts command-line arguments to the Go values Call and
+// Transact expect for method's inputs.  Integers may be decimal or 0x
+// hex, bytes are 0x hex; arrays and tuples are not supported.
+func (c *StorageClient) ParseArgs(method string, raw []string) ([]interface{}, error) {
+	m, ok := c.abi.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %q not found in contract abi", method)
+	}
+	if len(raw) != len(m.Inputs) {
+		return nil, fmt.Errorf("%w: %s takes %d arguments, got %d", ErrInvalidArgument, method, len(m.Inputs), len(raw))
+	}
+	args := make([]interface{}, len(raw))
+	for i, in := range m.Inputs {
+		arg, err := parseArg(in.Type, raw[i])
+		if err != nil {
+			name := in.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			return nil, fmt.Errorf("%w: %s argument %s: %v", ErrInvalidArgument, method, name, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// parseArg converts s to the Go type of abi type t.
+func parseArg(t abi.Type, s string) (interface{}, error) {
+	typ := t.GetType()
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q", t, s)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return nil, fmt.Errorf("%s cannot be negative", t)
+		}
+		if typ == reflect.TypeOf((*big.Int)(nil)) {
+			return n, nil
+		}
+		v := reflect.New(typ).Elem()
+		if t.T == abi.UintTy {
+			if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+				return nil, fmt.Errorf("%s overflows %s", s, t)
+			}
+			v.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+				return nil, fmt.Errorf("%s overflows %s", s, t)
+			}
+			v.SetInt(n.Int64())
+		}
+		return v.Interface(), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.StringTy:
+		return s, nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return common.HexToAddress(s), nil
+	case abi.BytesTy:
+		return hexutil.Decode(s)
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("%s needs %d bytes, got %d", t, t.Size, len(b))
+		}
+		v := reflect.New(typ).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface(), nil
+	default:
+		return nil, fmt.Errorf("type %s cannot be given on the command line", t)
+	}
+}
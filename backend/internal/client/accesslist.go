@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+// ErrAccessListUnsupported is returned by CreateAccessList when the node
+// does not implement eth_createAccessList, or the client has no url to
+// reach it with.
+var ErrAccessListUnsupported = errors.New("node does not support eth_createAccessList")
+
+// ErrTxTypeUnsupported is returned when creating a client whose FeeMode
+// selects a transaction type the chain does not accept.
+var ErrTxTypeUnsupported = errors.New("transaction type not supported by the chain")
+
+type accessListKey struct{}
+
+// WithAccessList returns a context under which transactions the client
+// sends carry list.  Dynamic-fee transactions keep their type; the others
+// are sent as EIP-2930 access-list transactions, so FeeModeLegacy cannot
+// be combined with an access list.
+func WithAccessList(ctx context.Context, list types.AccessList) context.Context {
+	return context.WithValue(ctx, accessListKey{}, list)
+}
+
+// accessListFrom returns the list attached to ctx by WithAccessList.
+func accessListFrom(ctx context.Context) (types.AccessList, bool) {
+	list, ok := ctx.Value(accessListKey{}).(types.AccessList)
+	return list, ok
+}
+
+// CreateAccessList asks the node, with eth_createAccessList, which
+// accounts and storage slots calling method with args touches, and how
+// much gas the call uses once they are listed.  Attach the list with
+// WithAccessList; with FeeModeAccessList, Set, Add and Sub generate one
+// themselves.
+//
+// Nodes leave the sender and the contract itself out of the list, both
+// being warm anyway, so a call that only touches the contract's own
+// storage gets an empty list.
+func (c *StorageClient) CreateAccessList(ctx context.Context, method string, args ...interface{}) (types.AccessList, uint64, error) {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	value, err := txValue(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	client, err := c.rawRPC(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	call := map[string]interface{}{
+		"from":  c.from,
+		"to":    c.address,
+		"data":  hexutil.Bytes(data),
+		"value": (*hexutil.Big)(value),
+	}
+	var res struct {
+		AccessList types.AccessList `json:"accessList"`
+		GasUsed    hexutil.Uint64   `json:"gasUsed"`
+		Error      string           `json:"error,omitempty"`
+	}
+	err = c.retry(ctx, func() error {
+		return client.CallContext(ctx, &res, "eth_createAccessList", call, "latest")
+	})
+	if err != nil {
+		if isMethodNotFound(err) {
+			return nil, 0, fmt.Errorf("%w: %w", ErrAccessListUnsupported, err)
+		}
+		return nil, 0, fmt.Errorf("creating access list for %s: %w", method, classifyNodeError(err))
+	}
+	if res.Error != "" {
+		return nil, 0, fmt.Errorf("creating access list for %s: %w", method, &RevertError{Reason: res.Error})
+	}
+	return res.AccessList, uint64(res.GasUsed), nil
+}
+
+// rawRPC returns a plain RPC connection to cfg.RPCURL, dialed on first
+// use, for methods ContractBackend does not expose.
+func (c *StorageClient) rawRPC(ctx context.Context) (*rpc.Client, error) {
+	c.rpcMu.Lock()
+	defer c.rpcMu.Unlock()
+	if c.rpc != nil {
+		return c.rpc, nil
+	}
+	if c.cfg.RPCURL == "" {
+		return nil, fmt.Errorf("%w: rpc url not set", ErrAccessListUnsupported)
+	}
+	client, err := dialRPC(ctx, c.cfg, c.cfg.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", redactURL(c.cfg.RPCURL), redactURLError(err))
+	}
+	c.rpc = client
+	return client, nil
+}
+
+// isMethodNotFound reports whether err is the JSON-RPC error for an
+// unknown method.
+func isMethodNotFound(err error) bool {
+	var re rpc.Error
+	return errors.As(err, &re) && re.ErrorCode() == -32601
+}
+
+// attachAccessList makes auth sign its transaction with the access list
+// attached to ctx, or an empty one under FeeModeAccessList.
+func (c *StorageClient) attachAccessList(ctx context.Context, auth *bind.TransactOpts, chainID *big.Int) error {
+	list, ok := accessListFrom(ctx)
+	if !ok && c.cfg.FeeMode != FeeModeAccessList {
+		return nil
+	}
+	if c.cfg.FeeMode == FeeModeLegacy {
+		return fmt.Errorf("%w: legacy transactions cannot carry an access list, use FEE_MODE=%s", ErrInvalidArgument, FeeModeAccessList)
+	}
+	sign := auth.Signer
+	auth.Signer = func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		return sign(from, withAccessList(tx, chainID, list))
+	}
+	return nil
+}
+
+// withAccessList copies tx with list attached, turning a legacy
+// transaction into an EIP-2930 one.
+func withAccessList(tx *types.Transaction, chainID *big.Int, list types.AccessList) *types.Transaction {
+	if tx.Type() == types.DynamicFeeTxType {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tx.GasTipCap(),
+			GasFeeCap:  tx.GasFeeCap(),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: list,
+		})
+	}
+	return types.NewTx(&types.AccessListTx{
+		ChainID:    chainID,
+		Nonce:      tx.Nonce(),
+		GasPrice:   tx.GasPrice(),
+		Gas:        tx.Gas(),
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: list,
+	})
+}
+
+// checkTxType fails with ErrTxTypeUnsupported if the chain does not accept
+// the transactions cfg.FeeMode selects.  A chain with a base fee has had
+// both the London and the earlier Berlin upgrade; otherwise access lists
+// are taken as supported if the node can create one.  FeeModeDynamic
+// already falls back to legacy pricing without a base fee, so it is only
+// warned about.
+func (c *StorageClient) checkTxType(ctx context.Context) error {
+	if c.cfg.ReadOnly || c.cfg.FeeMode == FeeModeLegacy || (c.cfg.FeeMode == FeeModeDynamic && c.cfg.GasPrice != nil) {
+		return nil
+	}
+	var head *types.Header
+	err := c.retry(ctx, func() (err error) {
+		head, err = c.client.HeaderByNumber(ctx, nil)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("fetching latest header: %w", err)
+	}
+	if head.BaseFee != nil {
+		return nil
+	}
+	if c.cfg.FeeMode == FeeModeDynamic {
+		c.cfg.Logger.WarnContext(ctx, "chain has no base fee, sending legacy transactions instead of dynamic-fee ones")
+		return nil
+	}
+	if _, _, err := c.CreateAccessList(ctx, "get"); err != nil {
+		return fmt.Errorf("%w: FEE_MODE=%s needs the Berlin upgrade: %w", ErrTxTypeUnsupported, c.cfg.FeeMode, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// accumulator buffers the deltas given to AccumulateAdd until Commit.
+type accumulator struct {
+	mu    sync.Mutex
+	total *big.Int // nil when nothing is buffered
+}
+
+// AccumulateAdd buffers delta locally, to be added to the stored value by
+// the next Commit.  Nothing is sent to the node.
+func (c *StorageClient) AccumulateAdd(delta *big.Int) error {
+	if delta == nil || delta.Sign() < 0 {
+		return fmt.Errorf("%w: delta must be a non-negative integer, got %v", ErrValueOutOfRange, delta)
+	}
+	c.accumulated.add(delta)
+	return nil
+}
+
+// add buffers delta.
+func (a *accumulator) add(delta *big.Int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.total == nil {
+		a.total = new(big.Int)
+	}
+	a.total.Add(a.total, delta)
+}
+
+// Accumulated returns the sum of the deltas buffered since the last
+// Commit.
+func (c *StorageClient) Accumulated() *big.Int {
+	c.accumulated.mu.Lock()
+	defer c.accumulated.mu.Unlock()
+	if c.accumulated.total == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(c.accumulated.total)
+}
+
+// Commit applies the deltas buffered by AccumulateAdd in one transaction.
+// With nothing buffered it sends nothing and returns a nil result.
+//
+// By default Commit reads the stored value and Sets it to that value plus
+// the buffered total.  This is the cheapest call the contract offers, but
+// it is not atomic: a write by anyone else between the read and the Set
+// is overwritten.  With cfg.StrictCommit, Commit instead sends a single
+// Add of the total, which the contract applies on top of whatever value
+// it holds when the transaction is mined.
+//
+// If Commit fails the deltas are buffered again, so calling it once more
+// retries them.  When the error says the transaction may still be mined,
+// check it with TxStatus first or the deltas may be applied twice.
+func (c *StorageClient) Commit(ctx context.Context) (*TxResult, error) {
+	c.accumulated.mu.Lock()
+	total := c.accumulated.total
+	c.accumulated.total = nil
+	c.accumulated.mu.Unlock()
+	if total == nil {
+		return nil, nil
+	}
+
+	result, err := c.commit(ctx, total)
+	if err != nil {
+		c.accumulated.add(total)
+	}
+	return result, err
+}
+
+// commit sends total as described by Commit.
+func (c *StorageClient) commit(ctx context.Context, total *big.Int) (*TxResult, error) {
+	if c.cfg.StrictCommit {
+		return c.Add(ctx, total)
+	}
+	current, err := c.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading value to commit onto: %w", err)
+	}
+	return c.Set(ctx, new(big.Int).Add(current, total))
+}
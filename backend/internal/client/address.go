@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+)
+
+// ErrContractNotDeployed is returned when the configured contract address
+// holds no contract code, usually because it points at the wrong network
+// or at an externally owned account.
+var ErrContractNotDeployed = errors.New("no contract code at address")
+
+// ParseAddress parses a hex-encoded contract address.  Unlike
+// common.HexToAddress it rejects input of the wrong length or with invalid
+// hex digits, and mixed-case input must carry a valid EIP-55 checksum.
+func ParseAddress(s string) (common.Address, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return common.Address{}, fmt.Errorf("empty address")
+	}
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %q: want 20 hex-encoded bytes", s)
+	}
+	addr := common.HexToAddress(s)
+
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if want := addr.Hex(); digits != want[2:] {
+			return common.Address{}, fmt.Errorf("invalid address %q: bad EIP-55 checksum, want %s", s, want)
+		}
+	}
+	return addr, nil
+}
+
+// ParseHash parses a hex-encoded 32-byte hash, such as a transaction hash.
+func ParseHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(strings.TrimSpace(s))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid hash %q: %w", s, err)
+	}
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid hash %q: want %d bytes, got %d", s, common.HashLength, len(b))
+	}
+	return common.BytesToHash(b), nil
+}
+
+// checkCode fails with ErrContractNotDeployed if there is no contract
+// deployed at address.
+func (c *StorageClient) checkCode(ctx context.Context, address common.Address) error {
+	var code []byte
+	err := c.retry(ctx, func() (err error) {
+		code, err = c.client.CodeAt(ctx, address, nil)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("reading code at %s: %w", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w %s", ErrContractNotDeployed, address.Hex())
+	}
+	return nil
+}
+
+// CheckContract fails with ErrContractNotDeployed if the bound
+// contract's code is no longer present, for example after the chain was
+// reset.
+func (c *StorageClient) CheckContract(ctx context.Context) error {
+	return c.checkCode(ctx, c.address)
+}
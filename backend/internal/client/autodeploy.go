@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// defaultDeployStateFile is where AutoDeploy remembers the contracts it
+// deployed.
+const defaultDeployStateFile = ".simple-storage-deploy.json"
+
+// ErrAutoDeployRefused is returned when AutoDeploy would deploy a
+// contract on a chain that is not a known development chain.
+var ErrAutoDeployRefused = errors.New("AUTO_DEPLOY is only allowed on development chains")
+
+// devChainIDs are the chain IDs AutoDeploy may deploy on: geth --dev,
+// Ganache and Hardhat or Anvil.  Anything else, mainnet in particular,
+// needs an explicit deploy.
+var devChainIDs = map[uint64]bool{
+	1337:  true,
+	31337: true,
+}
+
+// deployState is the content of cfg.DeployStateFile: the contract deployed
+// on each chain, keyed by decimal chain ID.
+type deployState struct {
+	Contracts map[string]common.Address `json:"contracts"`
+}
+
+// bindOrDeploy binds the contract at address, or deploys a new one if
+// there is none.  A zero address means the one AutoDeploy recorded for
+// this chain in cfg.DeployStateFile, if any.  Deploying is refused with
+// ErrAutoDeployRefused on chains other than local development ones.
+func (c *StorageClient) bindOrDeploy(ctx context.Context, address common.Address) error {
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return err
+	}
+	state, err := loadDeployState(c.cfg.DeployStateFile)
+	if err != nil {
+		return err
+	}
+	if address == (common.Address{}) {
+		address = state.Contracts[chainID.String()]
+	}
+
+	if address != (common.Address{}) {
+		err := c.checkCode(ctx, address)
+		if err == nil {
+			return c.bind(address)
+		}
+		if !errors.Is(err, ErrContractNotDeployed) {
+			return err
+		}
+	}
+
+	if !chainID.IsUint64() || !devChainIDs[chainID.Uint64()] {
+		return fmt.Errorf("%w: chain id is %s", ErrAutoDeployRefused, chainID)
+	}
+	deployed, err := c.deploy(ctx, big.NewInt(0))
+	if err != nil {
+		return fmt.Errorf("auto-deploying contract: %w", err)
+	}
+	c.cfg.Logger.WarnContext(ctx, "AUTO_DEPLOY: deployed a new SimpleStorage contract",
+		"address", deployed.Hex(), "chainId", chainID, "stateFile", c.cfg.DeployStateFile)
+
+	state.Contracts[chainID.String()] = deployed
+	if err := state.save(c.cfg.DeployStateFile); err != nil {
+		return fmt.Errorf("recording deployed contract %s: %w", deployed.Hex(), err)
+	}
+	return nil
+}
+
+// loadDeployState reads the state file at path.  A missing file is an
+// empty state.
+func loadDeployState(path string) (*deployState, error) {
+	state := &deployState{Contracts: make(map[string]common.Address)}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if state.Contracts == nil {
+		state.Contracts = make(map[string]common.Address)
+	}
+	return state, nil
+}
+
+// save writes s to path.
+func (s *deployState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// ContractBackend is the subset of *jumboclient.Client the StorageClient
+// uses.  Depending on it rather than the concrete client lets tests and
+// wrappers stand in for a live node.
+type ContractBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error)
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	Close()
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+)
+
+// ErrInsufficientFunds is returned when the sender cannot pay for the
+// transaction it is about to submit.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Balance returns the sender's balance in wei at the latest block.
+func (c *StorageClient) Balance(ctx context.Context) (*big.Int, error) {
+	var balance *big.Int
+	err := c.retry(ctx, func() (err error) {
+		balance, err = c.client.BalanceAt(ctx, c.from, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching balance of %s: %w", c.from.Hex(), err)
+	}
+	return balance, nil
+}
+
+// checkBalance fails with ErrInsufficientFunds if the sender's balance does
+// not cover the worst-case cost of a transaction sent with auth: the full
+// gas limit at the highest price it may pay, plus any value it carries.
+func (c *StorageClient) checkBalance(ctx context.Context, auth *bind.TransactOpts) error {
+	price := auth.GasFeeCap
+	if price == nil {
+		price = auth.GasPrice
+	}
+	cost := new(big.Int).SetUint64(auth.GasLimit)
+	if price != nil {
+		cost.Mul(cost, price)
+	}
+	if auth.Value != nil {
+		cost.Add(cost, auth.Value)
+	}
+
+	balance, err := c.Balance(ctx)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w: %s has %s wei, transaction may cost up to %s wei", ErrInsufficientFunds, auth.From.Hex(), balance, cost)
+	}
+	return nil
+}
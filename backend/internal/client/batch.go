@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// SetBatch stores each of values in turn.  All transactions are submitted
+// back to back with consecutive nonces before any receipt is awaited, so
+// the batch takes roughly as long as a single Set.  The contract ends up
+// holding the last value.
+//
+// Every value is checked before anything is sent, so one out of range
+// fails the whole batch with ErrValueOutOfRange and no nonce used.
+//
+// The returned slice has one entry per value that was submitted, in order.
+// If a submission fails the values after it are not sent; if a transaction
+// fails or cannot be awaited its entry may be nil.  In both cases the
+// results gathered so far are returned along with the first error.
+func (c *StorageClient) SetBatch(ctx context.Context, values []*big.Int) (_ []*TxResult, err error) {
+	defer c.observe("set_batch", time.Now(), nil, &err)
+
+	for i, value := range values {
+		if err := c.checkValue(value); err != nil {
+			return nil, fmt.Errorf("value %d of %d: %w", i+1, len(values), err)
+		}
+	}
+	if c.cfg.ReadOnly {
+		return nil, ErrReadOnly
+	}
+
+	if c.cfg.DryRun {
+		results := make([]*TxResult, 0, len(values))
+		for _, value := range values {
+			res, err := c.dryRun(ctx, "set", []interface{}{value})
+			if err != nil {
+				return results, err
+			}
+			results = append(results, res)
+		}
+		return results, nil
+	}
+
+	var (
+		txs       []*types.Transaction
+		submitErr error
+	)
+	for i, value := range values {
+		tx, err := c.submit(ctx, "set", []interface{}{value}, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+			return c.instance.Set(auth, value)
+		})
+		if err != nil {
+			submitErr = fmt.Errorf("value %d of %d: %w", i+1, len(values), err)
+			break
+		}
+		txs = append(txs, tx)
+	}
+
+	results := make([]*TxResult, len(txs))
+	firstErr := submitErr
+	if c.cfg.NoWait {
+		for i, tx := range txs {
+			results[i] = c.submitted(ctx, "set", []interface{}{values[i]}, tx)
+		}
+		return results, firstErr
+	}
+	for i, tx := range txs {
+		res, err := c.wait(ctx, "set", tx)
+		c.record(ctx, "set", []interface{}{values[i]}, tx, res, err)
+		results[i] = res
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("value %d of %d: %w", i+1, len(values), err)
+		}
+	}
+	return results, firstErr
+}
@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+const (
+	defaultBreakerWindow   = 30 * time.Second
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without contacting the node while the
+// circuit breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of the circuit breaker around the node.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every request until the cooldown has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets a single request through to probe whether the
+	// node has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// breakerBackend fails requests fast once the node has failed threshold
+// times in a row within window.  After cooldown one request is let
+// through; if it succeeds the breaker closes, otherwise it opens again.
+// Only network and availability failures count; a revert or rejected
+// transaction shows that the node is answering.
+type breakerBackend struct {
+	ContractBackend
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	logger    *slog.Logger
+
+	mu           sync.Mutex
+	state        BreakerState
+	failures     int       // consecutive failures in the current streak
+	firstFailure time.Time // when the current streak began
+	openedAt     time.Time
+	probing      bool // a half-open probe is in flight
+}
+
+// newBreaker wraps backend in a circuit breaker configured by cfg, or
+// returns nil if cfg.BreakerThreshold is not set.
+func newBreaker(cfg Config, backend ContractBackend) *breakerBackend {
+	if cfg.BreakerThreshold <= 0 {
+		return nil
+	}
+	return &breakerBackend{
+		ContractBackend: backend,
+		threshold:       cfg.BreakerThreshold,
+		window:          cfg.BreakerWindow,
+		cooldown:        cfg.BreakerCooldown,
+		logger:          cfg.Logger,
+	}
+}
+
+// State reports the breaker's current state.  An open breaker whose
+// cooldown has passed reports BreakerHalfOpen.
+func (b *breakerBackend) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, moving an open breaker to
+// half-open once its cooldown has passed.
+func (b *breakerBackend) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+			return fmt.Errorf("%w: retrying the node in %s", ErrCircuitOpen, wait.Round(time.Second))
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w: waiting for the node to recover", ErrCircuitOpen)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a request.
+func (b *breakerBackend) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) && !errors.Is(err, ErrTimeout):
+		// The caller gave up, which says nothing about the node.
+		b.probing = false
+	case err == nil || !isTransient(err):
+		if b.state != BreakerClosed {
+			b.logger.Info("node recovered, circuit breaker closed")
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		b.probing = false
+	case b.state == BreakerHalfOpen:
+		b.open(err)
+	default:
+		now := time.Now()
+		if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+			b.failures = 0
+			b.firstFailure = now
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open(err)
+		}
+	}
+}
+
+func (b *breakerBackend) open(err error) {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.probing = false
+	b.logger.Warn("node failing, circuit breaker opened", "err", err, "cooldown", b.cooldown)
+}
+
+func (b *breakerBackend) SubscribeFilterLogs(ctx context.Context, query jumbochain.FilterQuery, ch chan<- types.Log) (jumbochain.Subscription, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.SubscribeFilterLogs(ctx, query, ch)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.CodeAt(ctx, contract, blockNumber)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) CallContract(ctx context.Context, call jumbochain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.CallContract(ctx, call, blockNumber)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.PendingCallContract(ctx, call)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.HeaderByNumber(ctx, number)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.PendingCodeAt(ctx, account)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if err := b.allow(); err != nil {
+		return 0, err
+	}
+	v, err := b.ContractBackend.PendingNonceAt(ctx, account)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.SuggestGasPrice(ctx)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.SuggestGasTipCap(ctx)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) EstimateGas(ctx context.Context, call jumbochain.CallMsg) (uint64, error) {
+	if err := b.allow(); err != nil {
+		return 0, err
+	}
+	v, err := b.ContractBackend.EstimateGas(ctx, call)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.ContractBackend.SendTransaction(ctx, tx)
+	b.record(err)
+	return err
+}
+
+func (b *breakerBackend) FilterLogs(ctx context.Context, query jumbochain.FilterQuery) ([]types.Log, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.FilterLogs(ctx, query)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.TransactionReceipt(ctx, txHash)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.ChainID(ctx)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := b.allow(); err != nil {
+		return 0, err
+	}
+	v, err := b.ContractBackend.BlockNumber(ctx)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if err := b.allow(); err != nil {
+		return nil, false, err
+	}
+	tx, pending, err := b.ContractBackend.TransactionByHash(ctx, hash)
+	b.record(err)
+	return tx, pending, err
+}
+
+func (b *breakerBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.BalanceAt(ctx, account, blockNumber)
+	b.record(err)
+	return v, err
+}
+
+func (b *breakerBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	v, err := b.ContractBackend.StorageAt(ctx, account, key, blockNumber)
+	b.record(err)
+	return v, err
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+const (
+	// minGasBumpPercent is the smallest fee increase nodes accept for a
+	// replacement transaction.
+	minGasBumpPercent = 10
+
+	defaultMaxGasBumps = 3
+)
+
+// ResubmitWithHigherGas replaces the pending transaction tx with a copy
+// that has the same nonce and calldata but fees raised by bumpPercent.
+// Percentages below the 10% replacement minimum are raised to it.  The new
+// transaction is returned once it has been submitted.
+func (c *StorageClient) ResubmitWithHigherGas(ctx context.Context, tx *types.Transaction, bumpPercent uint64) (*types.Transaction, error) {
+	bumpPercent = max(bumpPercent, minGasBumpPercent)
+
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inner types.TxData
+	switch tx.Type() {
+	case types.LegacyTxType:
+		inner = &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: bumpFee(tx.GasPrice(), bumpPercent),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}
+	case types.AccessListTxType:
+		inner = &types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasPrice:   bumpFee(tx.GasPrice(), bumpPercent),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}
+	case types.DynamicFeeTxType:
+		inner = &types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bumpFee(tx.GasTipCap(), bumpPercent),
+			GasFeeCap:  bumpFee(tx.GasFeeCap(), bumpPercent),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}
+	default:
+		return nil, fmt.Errorf("cannot bump transaction of type %d", tx.Type())
+	}
+
+	signed, err := signProtected(c.signer, types.NewTx(inner), chainID)
+	if err != nil {
+		return nil, fmt.Errorf("signing replacement: %w", err)
+	}
+	if err := c.client.SendTransaction(ctx, signed); err != nil {
+		return nil, fmt.Errorf("sending replacement for %s: %w", tx.Hash().Hex(), err)
+	}
+	return signed, nil
+}
+
+// bumpFee returns fee raised by percent, rounded up.
+func bumpFee(fee *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// waitWithWatchdog waits for tx like WaitConfirmed.  If cfg.MiningTimeout
+// is set and the transaction is not confirmed in time, it is resubmitted
+// with higher fees up to cfg.MaxGasBumps times, and the receipt returned is
+// that of whichever version was mined.  Running out of time fails with a
+// *MiningTimeoutError for the last version sent.
+func (c *StorageClient) waitWithWatchdog(ctx context.Context, tx *types.Transaction) (receipt *types.Receipt, err error) {
+	// A cached receipt was already confirmed by an earlier wait.
+	if receipt, ok := c.receipts.get(tx.Hash()); ok {
+		return receipt, nil
+	}
+	defer func() {
+		switch {
+		case err == nil:
+			c.receipts.put(receipt)
+		case errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrTimeout):
+			err = &MiningTimeoutError{TxHash: tx.Hash(), err: err}
+		}
+	}()
+
+	if c.cfg.MiningTimeout == 0 {
+		return WaitConfirmed(ctx, c.client, tx, c.cfg.Confirmations, c.cfg.PollInterval)
+	}
+
+	// Every version stays a candidate: an earlier, cheaper one may still
+	// be mined after it was replaced.
+	submitted := []*types.Transaction{tx}
+	for bumps := 0; ; bumps++ {
+		waitCtx, cancel := context.WithTimeout(ctx, c.cfg.MiningTimeout)
+		receipt, err := waitConfirmedAny(waitCtx, c.client, submitted, c.cfg.Confirmations, c.cfg.PollInterval)
+		cancel()
+		if err == nil || !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil || bumps >= c.cfg.MaxGasBumps {
+			return receipt, err
+		}
+
+		bumped, err := c.ResubmitWithHigherGas(ctx, tx, c.cfg.GasBumpPercent)
+		if err != nil {
+			// Most likely an earlier version was mined in the meantime.
+			c.cfg.Logger.WarnContext(ctx, "gas bump failed", "tx", tx.Hash(), "err", err)
+			return waitConfirmedAny(ctx, c.client, submitted, c.cfg.Confirmations, c.cfg.PollInterval)
+		}
+		c.cfg.Logger.InfoContext(ctx, "resubmitted transaction with higher fees", "old", tx.Hash(), "new", bumped.Hash())
+		tx = bumped
+		submitted = append(submitted, tx)
+	}
+}
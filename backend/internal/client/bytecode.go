@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+// runtimeCode is the hex encoded runtime code of the SimpleStorage build
+// this package ships: what the creation code in testdata/SimpleStorage.bin
+// leaves at the contract address.
+//
+//go:embed SimpleStorage.bin-runtime
+var runtimeCode string
+
+// runtimeCodeHash is the keccak256 hash of runtimeCode.
+var runtimeCodeHash = crypto.Keccak256Hash(common.FromHex(strings.TrimSpace(runtimeCode)))
+
+// ErrBytecodeMismatch is returned by VerifyBytecode when the code at the
+// contract address is not the expected SimpleStorage build.
+var ErrBytecodeMismatch = errors.New("contract code does not match the expected SimpleStorage build")
+
+// VerifyBytecode checks that the code deployed at the contract address
+// hashes to cfg.ExpectedCodeHash, or to the hash of the runtime code
+// embedded in this package if that is not set, so a client pointed at
+// some other contract fails with ErrBytecodeMismatch before it sends
+// anything.  NewStorageClientFromConfig runs it on connecting.  A contract
+// compiled differently, by solc for instance, needs EXPECTED_CODE_HASH
+// set to the hash of its own runtime code.
+func (c *StorageClient) VerifyBytecode(ctx context.Context) error {
+	want, err := c.expectedCodeHash()
+	if err != nil {
+		return err
+	}
+	var code []byte
+	err = c.retry(ctx, func() (err error) {
+		code, err = c.client.CodeAt(ctx, c.address, nil)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("reading code at %s: %w", c.address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w %s", ErrContractNotDeployed, c.address.Hex())
+	}
+	if got := crypto.Keccak256Hash(code); got != want {
+		return fmt.Errorf("%w: code at %s hashes to %s, want %s; set EXPECTED_CODE_HASH if it is another build of the contract",
+			ErrBytecodeMismatch, c.address.Hex(), got.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// expectedCodeHash returns the hash VerifyBytecode compares against.
+func (c *StorageClient) expectedCodeHash() (common.Hash, error) {
+	if c.cfg.ExpectedCodeHash == "" {
+		return runtimeCodeHash, nil
+	}
+	return ParseHash(c.cfg.ExpectedCodeHash)
+}
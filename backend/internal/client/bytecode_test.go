@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+func TestVerifyBytecode(t *testing.T) {
+	ctx := context.Background()
+	code, err := newMockBackend(t).CodeAt(ctx, mockContract, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mock's code is not the embedded build.
+	c, _ := newMockClient(t, newMockBackend(t), Config{})
+	if err := c.VerifyBytecode(ctx); !errors.Is(err, ErrBytecodeMismatch) {
+		t.Errorf("VerifyBytecode = %v, want ErrBytecodeMismatch", err)
+	}
+
+	c, _ = newMockClient(t, newMockBackend(t), Config{ExpectedCodeHash: crypto.Keccak256Hash(code).Hex()})
+	if err := c.VerifyBytecode(ctx); err != nil {
+		t.Errorf("VerifyBytecode with the code's hash expected = %v", err)
+	}
+
+	m := newMockBackend(t)
+	c, _ = newMockClient(t, m, Config{ExpectedCodeHash: crypto.Keccak256Hash(code).Hex()})
+	refused := errors.New("refused")
+	m.failWith("CodeAt", refused)
+	if err := c.VerifyBytecode(ctx); !errors.Is(err, refused) {
+		t.Errorf("VerifyBytecode with CodeAt failing = %v, want %v", err, refused)
+	}
+}
+
+// TestEmbeddedRuntimeCode checks that the embedded runtime code is what
+// the testdata creation code deploys.
+func TestEmbeddedRuntimeCode(t *testing.T) {
+	c, _ := newSimulatedClient(t, big.NewInt(1), Config{})
+	if err := c.VerifyBytecode(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+)
+
+// ErrGasEstimationUnsupported is returned in place of a gas estimate when
+// the node was found not to support eth_estimateGas.
+var ErrGasEstimationUnsupported = errors.New("node does not support gas estimation")
+
+// Capabilities lists what the node was found to support when the client
+// was created.
+type Capabilities struct {
+	// GasEstimation is false if estimating the gas of a read failed.
+	// Transactions are then sent with cfg.DefaultGasLimit.
+	GasEstimation bool
+}
+
+// Capabilities reports what the node was found to support.
+func (c *StorageClient) Capabilities() Capabilities {
+	return Capabilities{GasEstimation: !c.noGasEstimation}
+}
+
+// probeCapabilities checks what the node supports, so that missing
+// features are worked around from the first call instead of failing each
+// one.  Gas estimation is probed with a call to get, which cannot revert.
+// A FeeMode the chain cannot honour fails the probe.
+func (c *StorageClient) probeCapabilities(ctx context.Context) error {
+	if err := c.checkTxType(ctx); err != nil {
+		return err
+	}
+	data, err := c.abi.Pack("get")
+	if err != nil {
+		return err
+	}
+	err = c.retry(ctx, func() error {
+		_, err := c.client.EstimateGas(ctx, jumbochain.CallMsg{From: c.from, To: &c.address, Data: data})
+		return err
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		c.noGasEstimation = true
+		c.cfg.Logger.WarnContext(ctx, "node does not support gas estimation, sending every transaction with the default gas limit",
+			"gasLimit", c.cfg.DefaultGasLimit, "err", err)
+	}
+	return nil
+}
@@ -0,0 +1,758 @@
+// Package client wraps the generated SimpleStorage binding with the
+// connection, signing and receipt handling needed to use the contract
+// from other Go programs.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+	"github.com/jumbochain/jumbochain-go/jumboclient"
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+const (
+	// defaultGasBuffer is added on top of every gas estimate when no
+	// buffer is configured.
+	defaultGasBuffer = 20000
+	// defaultGasLimit is used until an estimate replaces it.
+	defaultGasLimit = 3000000
+)
+
+// StorageClient is a handle on a deployed SimpleStorage contract.  It owns
+// the RPC connection, the bound contract instance and the signing key.
+//
+// A StorageClient is safe for concurrent use once constructed.  Its
+// binding is fixed at construction, and the state it changes afterwards
+// (nonces, cached receipts, the supervised connections) is guarded by
+// locks.  Concurrent writes each reserve their own nonce, so they are
+// submitted without waiting for one another but may be mined in any
+// order.  Close must not race with other calls.
+type StorageClient struct {
+	cfg      Config
+	client   ContractBackend
+	address  common.Address
+	instance *storage.Storage
+	contract *bind.BoundContract // untyped binding used by Call and Transact
+	abi      *abi.ABI
+	signer   txSigner
+	from     common.Address
+	nonces   *NonceManager
+	receipts *receiptCache
+
+	idempotency *idempotencyCache
+	accumulated accumulator
+
+	// relayMu serializes access to cfg.RelayNonceFile.
+	relayMu sync.Mutex
+
+	// rpc is a raw connection for eth_createAccessList, dialed on first
+	// use under rpcMu.
+	rpcMu sync.Mutex
+	rpc   *rpc.Client
+
+	// ws is the separate subscription connection to cfg.WSURL, if any,
+	// and watcher the contract bound over whichever connection carries
+	// subscriptions.
+	ws      ContractBackend
+	watcher *storage.Storage
+
+	// conns are the supervised connections and pools behind client and
+	// ws, whose state ConnState reports.
+	conns []connState
+
+	// breaker is the circuit breaker in front of client, if enabled.
+	breaker *breakerBackend
+
+	// ownerless is set once the contract is found to have no owner(),
+	// so transactions stop checking for one.
+	ownerless atomic.Bool
+
+	// feeSource suggests fees: the node, or the configured gas oracle.
+	feeSource feeSource
+
+	// gasPrices caches suggested fees when cfg.GasPriceTTL is set, and
+	// stopGasPrices stops its refresher.
+	gasPrices     *gasPriceCache
+	stopGasPrices context.CancelFunc
+
+	// noGasEstimation is set when the node failed the gas estimation
+	// probe, so transactions use cfg.DefaultGasLimit.
+	noGasEstimation bool
+}
+
+// NewStorageClient dials rpcURL and binds the SimpleStorage contract at
+// contractAddress.  Transactions are signed with privateKeyHex; use
+// NewStorageClientFromConfig to sign with a keystore file instead.
+func NewStorageClient(ctx context.Context, rpcURL, contractAddress, privateKeyHex string) (*StorageClient, error) {
+	return NewStorageClientFromConfig(ctx, Config{
+		RPCURL:          rpcURL,
+		ContractAddress: contractAddress,
+		PrivateKey:      privateKeyHex,
+	})
+}
+
+// NewStorageClientFromConfig is like NewStorageClient but takes every
+// setting from cfg.
+//
+// With cfg.AutoDeploy, a missing contract is deployed instead of reported;
+// see bindOrDeploy.  Either way the code at the address must pass
+// VerifyBytecode.
+func NewStorageClientFromConfig(ctx context.Context, cfg Config) (*StorageClient, error) {
+	if cfg.ContractAddress == "" && !cfg.AutoDeploy {
+		return nil, fmt.Errorf("contract address not set")
+	}
+	var address common.Address
+	if cfg.ContractAddress != "" {
+		var err error
+		if address, err = ParseAddress(cfg.ContractAddress); err != nil {
+			return nil, fmt.Errorf("contract address: %w", err)
+		}
+	}
+	c, err := dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AutoDeploy {
+		err = c.bindOrDeploy(ctx, address)
+	} else {
+		err = c.bindExisting(ctx, address)
+	}
+	if err == nil {
+		err = c.VerifyBytecode(ctx)
+	}
+	if err == nil {
+		err = c.probeCapabilities(ctx)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewStorageClientWithBackend binds the contract at cfg.ContractAddress
+// over an existing backend connection instead of dialing cfg.RPCURL.  The
+// client takes ownership of backend and closes it in Close.  backend is
+// used as is: cfg.CallTimeout and cfg.RateLimit are not applied to it.
+func NewStorageClientWithBackend(ctx context.Context, cfg Config, backend ContractBackend) (*StorageClient, error) {
+	if cfg.ContractAddress == "" {
+		return nil, fmt.Errorf("contract address not set")
+	}
+	address, err := ParseAddress(cfg.ContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("contract address: %w", err)
+	}
+	c, err := newStorageClient(cfg, backend)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.bindExisting(ctx, address); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := c.probeCapabilities(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// dial connects to the node described by cfg and returns a client that is
+// not yet bound to a contract.
+func dial(ctx context.Context, cfg Config) (*StorageClient, error) {
+	if cfg.RPCURL == "" {
+		return nil, fmt.Errorf("rpc url not set")
+	}
+	cfg.setDefaults()
+
+	backend, conn, err := dialPooled(ctx, cfg, cfg.RPCURL)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := guard(cfg, backend)
+	breaker := newBreaker(cfg, wrapped)
+	if breaker != nil {
+		wrapped = breaker
+	}
+	c, err := newStorageClient(cfg, wrapped)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	if conn != nil {
+		c.conns = append(c.conns, conn)
+	}
+	c.breaker = breaker
+	if err := c.dialSubscriptions(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// dialBackend opens an RPC connection to url, retrying transient failures.
+// The transport (http, websocket or IPC) follows from the url.
+func dialBackend(ctx context.Context, cfg Config, url string) (*jumboclient.Client, error) {
+	var client *jumboclient.Client
+	err := withRetry(ctx, cfg.RetryAttempts, cfg.RetryBackoff, func() (err error) {
+		client, err = dialClient(ctx, cfg, url)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", redactURL(url), redactURLError(err))
+	}
+	return client, nil
+}
+
+// newStorageClient wraps backend in a client that is not yet bound to a
+// contract.
+func newStorageClient(cfg Config, backend ContractBackend) (*StorageClient, error) {
+	cfg.setDefaults()
+
+	signer, err := newSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("parsing contract abi: %w", err)
+	}
+	if cfg.ABIFile != "" {
+		if parsed, err = loadABI(cfg.ABIFile, parsed); err != nil {
+			signer.Close()
+			return nil, err
+		}
+	}
+
+	c := &StorageClient{
+		cfg:      cfg,
+		client:   backend,
+		abi:      parsed,
+		signer:   signer,
+		from:     signer.Address(),
+		nonces:   NewNonceManager(backend),
+		receipts: newReceiptCache(cfg.ReceiptCacheSize, cfg.ReceiptCacheTTL),
+
+		idempotency: newIdempotencyCache(cfg.IdempotencyTTL),
+		feeSource:   feeSourceFor(cfg, backend),
+	}
+	if cfg.GasPriceTTL > 0 {
+		var ctx context.Context
+		ctx, c.stopGasPrices = context.WithCancel(context.Background())
+		c.gasPrices = newGasPriceCache(cfg, c.feeSource)
+		go c.gasPrices.run(ctx)
+	}
+	return c, nil
+}
+
+// bind points the client at the contract deployed at address.
+func (c *StorageClient) bind(address common.Address) error {
+	instance, err := storage.NewStorage(address, c.client)
+	if err != nil {
+		return fmt.Errorf("binding contract: %w", err)
+	}
+	c.address = address
+	c.instance = instance
+	c.contract = bind.NewBoundContract(address, *c.abi, c.client, c.client, c.client)
+	c.watcher = instance
+	if c.ws != nil {
+		if c.watcher, err = storage.NewStorage(address, c.ws); err != nil {
+			return fmt.Errorf("binding contract: %w", err)
+		}
+	}
+	return nil
+}
+
+// bindExisting is like bind but first checks that a contract is actually
+// deployed at address, so a wrong address fails here rather than on the
+// first call.
+func (c *StorageClient) bindExisting(ctx context.Context, address common.Address) error {
+	if err := c.checkCode(ctx, address); err != nil {
+		return err
+	}
+	return c.bind(address)
+}
+
+// Close releases the underlying RPC connection and wipes the signing key
+// from memory.  The client must not be used afterwards.
+func (c *StorageClient) Close() {
+	if c.stopGasPrices != nil {
+		c.stopGasPrices()
+	}
+	c.client.Close()
+	if c.ws != nil {
+		c.ws.Close()
+	}
+	if c.rpc != nil {
+		c.rpc.Close()
+	}
+	c.signer.Close()
+}
+
+// ConnState reports whether the client's connections to the node are up.
+// It is ConnReconnecting while any of them is being re-dialed after being
+// lost.  Clients over http, or built around a caller's backend, always
+// report ConnConnected.
+func (c *StorageClient) ConnState() ConnState {
+	for _, conn := range c.conns {
+		if conn.State() == ConnReconnecting {
+			return ConnReconnecting
+		}
+	}
+	return ConnConnected
+}
+
+// BreakerState reports the state of the client's circuit breaker.  It is
+// always BreakerClosed when cfg.BreakerThreshold is not set.
+func (c *StorageClient) BreakerState() BreakerState {
+	if c.breaker == nil {
+		return BreakerClosed
+	}
+	return c.breaker.State()
+}
+
+// Address returns the address of the bound contract.
+func (c *StorageClient) Address() common.Address {
+	return c.address
+}
+
+// From returns the address transactions are sent from.
+func (c *StorageClient) From() common.Address {
+	return c.from
+}
+
+// Get returns the currently stored value.
+func (c *StorageClient) Get(ctx context.Context) (*big.Int, error) {
+	return c.get(ctx, "get", nil, false)
+}
+
+// GetPending reads the stored value from the node's pending state, so it
+// reflects transactions that have been submitted but not yet mined.  Nodes
+// that do not track a pending block answer from the latest block instead,
+// in which case GetPending behaves like Get.
+func (c *StorageClient) GetPending(ctx context.Context) (*big.Int, error) {
+	return c.get(ctx, "get_pending", nil, true)
+}
+
+// ErrStateUnavailable is returned by GetAtBlock when the node no longer
+// holds the state of the requested block, as is usual for non-archive
+// nodes once a block is more than a few minutes old.
+var ErrStateUnavailable = errors.New("historical state not available, the node may have pruned it; use an archive node")
+
+// GetAtBlock reads the stored value as of the end of block blockNumber.
+func (c *StorageClient) GetAtBlock(ctx context.Context, blockNumber uint64) (*big.Int, error) {
+	value, err := c.get(ctx, "get_at_block", new(big.Int).SetUint64(blockNumber), false)
+	if err != nil && isMissingState(err) {
+		return nil, fmt.Errorf("reading block %d: %w: %w", blockNumber, ErrStateUnavailable, err)
+	}
+	return value, err
+}
+
+// isMissingState reports whether err is a node refusing a call because
+// the state it needs has been pruned.  Nodes only report this in prose, so
+// the messages of the common clients are matched.
+func isMissingState(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "missing trie node") ||
+		strings.Contains(msg, "historical state") ||
+		strings.Contains(msg, "state is not available") ||
+		strings.Contains(msg, "state not available")
+}
+
+// getCalldata calls get(), which takes no arguments.
+var getCalldata = crypto.Keccak256([]byte("get()"))[:4]
+
+// get reads the stored value as of block, the latest if nil, or from the
+// pending state, recording it as method.  It calls the node directly
+// rather than through the binding, which packs the call and unpacks its
+// uint256 result by reflection every time, on the hottest path there is.
+func (c *StorageClient) get(ctx context.Context, method string, block *big.Int, pending bool) (value *big.Int, err error) {
+	defer c.observe(method, time.Now(), nil, &err)
+
+	msg := jumbochain.CallMsg{To: &c.address, Data: getCalldata}
+	var out []byte
+	err = c.retry(ctx, func() (err error) {
+		if pending {
+			out, err = c.client.PendingCallContract(ctx, msg)
+		} else {
+			out, err = c.client.CallContract(ctx, msg, block)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != 32 {
+		return nil, fmt.Errorf("get returned %d bytes, expected a uint256", len(out))
+	}
+	return new(big.Int).SetBytes(out), nil
+}
+
+// Set stores value in the contract and waits for the transaction to be mined.
+// Set, Add and Sub fail with ErrValueOutOfRange, before contacting the
+// node, if their argument is negative, exceeds uint256 or lies outside
+// cfg.MinValue and cfg.MaxValue.
+//
+// With cfg.SkipIfUnchanged, Set first reads the stored value and sends
+// nothing if it already equals value, returning a result with Skipped set.
+// Another writer may change the value between the read and the would-be
+// write, so the contract is not guaranteed to hold value afterwards.
+func (c *StorageClient) Set(ctx context.Context, value *big.Int) (*TxResult, error) {
+	if err := c.checkValue(value); err != nil {
+		return nil, err
+	}
+	if c.cfg.SkipIfUnchanged {
+		current, err := c.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if current.Cmp(value) == 0 {
+			c.cfg.Logger.DebugContext(ctx, "value unchanged, not sending set", "value", value)
+			return &TxResult{Skipped: true, NewValue: current}, nil
+		}
+	}
+	return c.transact(ctx, "set", []interface{}{value}, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.instance.Set(auth, value)
+	})
+}
+
+// Add adds value to the stored value and waits for the transaction to be mined.
+func (c *StorageClient) Add(ctx context.Context, value *big.Int) (*TxResult, error) {
+	if err := c.checkValue(value); err != nil {
+		return nil, err
+	}
+	return c.transact(ctx, "add", []interface{}{value}, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.instance.Add(auth, value)
+	})
+}
+
+// Sub subtracts value from the stored value and waits for the transaction
+// to be mined.  It fails with ErrUnderflow if value exceeds the stored
+// value.
+func (c *StorageClient) Sub(ctx context.Context, value *big.Int) (*TxResult, error) {
+	if err := c.checkValue(value); err != nil {
+		return nil, err
+	}
+	result, err := c.transact(ctx, "sub", []interface{}{value}, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.instance.Sub(auth, value)
+	})
+	if err != nil && isUnderflow(result, err) {
+		return result, fmt.Errorf("%w: %w", ErrUnderflow, err)
+	}
+	return result, err
+}
+
+// transact estimates gas for calling method with args, submits the
+// transaction built by send and waits for its receipt.
+func (c *StorageClient) transact(ctx context.Context, method string, args []interface{}, send func(*bind.TransactOpts) (*types.Transaction, error)) (result *TxResult, err error) {
+	defer c.observe(method, time.Now(), &result, &err)
+
+	if c.cfg.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	c.warnIfNotOwner(ctx)
+	if c.cfg.DryRun {
+		result, err = c.dryRun(ctx, method, args)
+		return result, authorizationError(result, err)
+	}
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		result, err = c.transactOnce(ctx, key, method, args, send)
+	} else {
+		_, result, err = c.submitAndWait(ctx, method, args, send)
+	}
+	return result, authorizationError(result, err)
+}
+
+// submitAndWait submits the transaction built by send, waits for it and
+// records the outcome.  The transaction is nil if nothing was sent.
+func (c *StorageClient) submitAndWait(ctx context.Context, method string, args []interface{}, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, *TxResult, error) {
+	tx, err := c.submit(ctx, method, args, send)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.cfg.NoWait {
+		return tx, c.submitted(ctx, method, args, tx), nil
+	}
+	result, err := c.wait(ctx, method, tx)
+	c.record(ctx, method, args, tx, result, err)
+	return tx, result, err
+}
+
+// submit estimates gas for method and sends the transaction built by send
+// without waiting for it to be mined.
+func (c *StorageClient) submit(ctx context.Context, method string, args []interface{}, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	// Estimate gas *before* reserving a nonce so a failed estimate does
+	// not leave a gap.
+	gas, err := c.estimateGas(ctx, method, args...)
+	estimated := err == nil
+	if err != nil && !errors.Is(err, ErrGasEstimationUnsupported) {
+		if !c.cfg.GasEstimateFallback || ctx.Err() != nil {
+			return nil, fmt.Errorf("estimating gas for %s: %w", method, err)
+		}
+		c.cfg.Logger.WarnContext(ctx, "gas estimation failed, sending with the default gas limit",
+			"method", method, "gasLimit", c.cfg.DefaultGasLimit, "err", err)
+	}
+
+	if c.cfg.FeeMode == FeeModeAccessList {
+		if _, ok := accessListFrom(ctx); !ok {
+			list, listGas, err := c.CreateAccessList(ctx, method, args...)
+			switch {
+			case err == nil:
+				ctx = WithAccessList(ctx, list)
+				if estimated {
+					gas = max(gas, listGas)
+				}
+			case errors.Is(err, ErrAccessListUnsupported):
+				c.cfg.Logger.DebugContext(ctx, "sending with an empty access list", "method", method, "err", err)
+			default:
+				return nil, err
+			}
+		}
+	}
+
+	if c.cfg.Simulate {
+		limit := uint64(0)
+		if estimated {
+			limit = c.gasLimit(gas)
+		}
+		if err := c.preflight(ctx, method, limit, args); err != nil {
+			return nil, err
+		}
+	}
+
+	auth, err := c.getTransactionAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if estimated {
+		auth.GasLimit = c.gasLimit(gas)
+	}
+	if !c.cfg.SkipBalanceCheck {
+		if err := c.checkBalance(ctx, auth); err != nil {
+			c.nonces.Rollback(auth.From, auth.Nonce.Uint64())
+			return nil, err
+		}
+	}
+
+	// Re-signing the same nonce and calldata yields the same transaction,
+	// so resending after a network error cannot double-submit.
+	var tx *types.Transaction
+	for resyncs := 0; ; resyncs++ {
+		err = c.retry(ctx, func() (err error) {
+			tx, err = send(auth)
+			return err
+		})
+		if err == nil {
+			break
+		}
+		err = classifyNodeError(err)
+		if !isNonceMismatch(err) {
+			c.nonces.Rollback(auth.From, auth.Nonce.Uint64())
+			return nil, fmt.Errorf("sending %s transaction: %w", method, err)
+		}
+
+		// The local count has drifted from the chain, usually because
+		// the key is also used elsewhere.  Resync and try again.
+		c.nonces.Reset(auth.From)
+		if resyncs == maxNonceResyncs {
+			return nil, fmt.Errorf("sending %s transaction: %w", method, err)
+		}
+		nonce, nerr := c.nonces.Next(ctx, auth.From)
+		if nerr != nil {
+			return nil, fmt.Errorf("sending %s transaction: resyncing nonce: %w", method, nerr)
+		}
+		c.cfg.Logger.WarnContext(ctx, "nonce out of sync with the chain, resending with a fresh one",
+			"method", method, "rejected", auth.Nonce, "nonce", nonce, "err", err)
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	c.cfg.Logger.DebugContext(ctx, "transaction submitted", "method", method, "tx", tx.Hash(), "nonce", tx.Nonce())
+	return tx, nil
+}
+
+// wait blocks until tx is confirmed and summarises its receipt.  A
+// transaction that was mined but reverted returns both a result and an
+// error.
+func (c *StorageClient) wait(ctx context.Context, method string, tx *types.Transaction) (*TxResult, error) {
+	// Cancelling ctx only stops the wait; the transaction stays in the
+	// mempool and may still be mined.
+	receipt, err := c.waitWithWatchdog(ctx, tx)
+	if err != nil {
+		var timeout *MiningTimeoutError
+		if errors.As(err, &timeout) {
+			c.cfg.Logger.WarnContext(ctx, "transaction not mined in time", "method", method, "tx", timeout.TxHash)
+			return &TxResult{TxHash: timeout.TxHash, Pending: true}, err
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("stopped waiting for transaction %s, it may still be mined: %w", tx.Hash().Hex(), err)
+		}
+		return nil, fmt.Errorf("transaction %s mining failed: %w", tx.Hash().Hex(), err)
+	}
+	result := newTxResult(receipt)
+	result.Events = c.DecodeReceiptLogs(receipt)
+	c.cfg.Metrics.ObserveGas(method, result.GasUsed)
+	c.cfg.Logger.DebugContext(ctx, "transaction mined", "method", method, "tx", tx.Hash(),
+		"block", result.BlockNumber, "gasUsed", result.GasUsed, "status", result.Status)
+	if !result.Succeeded() {
+		result.RevertReason = c.revertReason(ctx, tx, receipt.BlockNumber)
+		return result, fmt.Errorf("transaction %s failed: %w", tx.Hash().Hex(), &RevertError{Reason: result.RevertReason})
+	}
+	return result, nil
+}
+
+// BlockNumber returns the number of the node's latest block.  It doubles
+// as a cheap connectivity check.
+func (c *StorageClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var n uint64
+	err := c.retry(ctx, func() (err error) {
+		n, err = c.client.BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetching block number: %w", err)
+	}
+	return n, nil
+}
+
+// gasLimit returns the limit to send with a transaction whose cost was
+// estimated at gas, padded by the configured flat and percentage buffers.
+func (c *StorageClient) gasLimit(gas uint64) uint64 {
+	return gas + c.cfg.GasBuffer + gas*c.cfg.GasBufferPercent/100
+}
+
+// estimateGas asks the node how much gas calling method with args would use.
+func (c *StorageClient) estimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error) {
+	if c.noGasEstimation {
+		return 0, ErrGasEstimationUnsupported
+	}
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return 0, err
+	}
+	value, err := txValue(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var gas uint64
+	err = c.retry(ctx, func() (err error) {
+		gas, err = c.client.EstimateGas(ctx, jumbochain.CallMsg{
+			From:  c.from,
+			To:    &c.address,
+			Value: value,
+			Data:  data,
+		})
+		return err
+	})
+	return gas, classifyNodeError(err)
+}
+
+// getTransactionAuthorizer creates a `bind.TransactOpts` struct
+// for signing and submitting transactions.  The returned nonce is reserved
+// and must be rolled back if the transaction is never submitted.
+func (c *StorageClient) getTransactionAuthorizer(ctx context.Context) (*bind.TransactOpts, error) {
+	if c.cfg.ReadOnly {
+		return nil, ErrReadOnly
+	}
+
+	// Chain ID is needed for EIP-155 signing.
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new `bind.TransactOpts` struct.  This struct holds
+	// all the necessary information for signing and sending a transaction.
+	auth, err := transactOpts(c.signer, chainID)
+	if err != nil {
+		return nil, err
+	}
+	auth.Context = ctx
+	// Amount to send (in wei), zero unless the caller attached one with
+	// WithTxValue.
+	if auth.Value, err = txValue(ctx); err != nil {
+		return nil, err
+	}
+	auth.GasLimit = c.cfg.DefaultGasLimit // Maximum gas allowed for the transaction.
+
+	if err := c.applyFees(ctx, auth); err != nil {
+		return nil, err
+	}
+	if err := c.attachAccessList(ctx, auth, chainID); err != nil {
+		return nil, err
+	}
+
+	// Reserve the next nonce for the sender's address.  This is done last
+	// so no other failure can leave the reservation dangling.
+	var nonce uint64
+	err = c.retry(ctx, func() (err error) {
+		nonce, err = c.nonces.Next(ctx, c.from)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching nonce: %w", err)
+	}
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+
+	return auth, nil
+}
+
+// ErrChainMismatch is returned when the node is on a different chain from
+// the one the configuration expects.
+var ErrChainMismatch = errors.New("chain id mismatch")
+
+// chainID returns the configured chain ID, or asks the node for it.  When
+// ExpectedChainID is set the node is always asked, and a node on any other
+// chain is refused.
+func (c *StorageClient) chainID(ctx context.Context) (*big.Int, error) {
+	expected := c.cfg.ExpectedChainID
+	if c.cfg.ChainID != nil && expected == nil {
+		return c.cfg.ChainID, nil
+	}
+	var id *big.Int
+	err := c.retry(ctx, func() (err error) {
+		id, err = c.client.ChainID(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching chain id: %w", err)
+	}
+	if expected != nil && id.Cmp(expected) != 0 {
+		return nil, fmt.Errorf("%w: node at %s is on chain %s, expected %s", ErrChainMismatch, redactURL(c.cfg.RPCURL), id, expected)
+	}
+	if c.cfg.ChainID != nil {
+		if expected != nil && c.cfg.ChainID.Cmp(expected) != 0 {
+			return nil, fmt.Errorf("%w: CHAIN_ID is %s, expected %s", ErrChainMismatch, c.cfg.ChainID, expected)
+		}
+		return c.cfg.ChainID, nil
+	}
+	return id, nil
+}
+
+// observe records the outcome of an operation on method that began at
+// start.  It is deferred with pointers to the operation's named results.
+func (c *StorageClient) observe(method string, start time.Time, result **TxResult, err *error) {
+	outcome := metrics.OutcomeSuccess
+	if *err != nil {
+		outcome = metrics.OutcomeError
+		if result != nil && *result != nil && !(*result).DryRun && !(*result).Succeeded() {
+			outcome = metrics.OutcomeRevert
+		}
+	}
+	c.cfg.Metrics.Observe(method, outcome, time.Since(start))
+}
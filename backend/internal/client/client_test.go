@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+func TestSetSendsSignedTransaction(t *testing.T) {
+	ctx := context.Background()
+	m := newMockBackend(t)
+	c, key := newMockClient(t, m, Config{})
+
+	result, err := c.Set(ctx, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Succeeded() {
+		t.Fatalf("status = %d, want success", result.Status)
+	}
+
+	sent := m.sentTxs()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(sent))
+	}
+	tx := sent[0]
+	if tx.Hash() != result.TxHash {
+		t.Errorf("result hash = %s, sent %s", result.TxHash, tx.Hash())
+	}
+	if to := tx.To(); to == nil || *to != mockContract {
+		t.Errorf("to = %v, want %s", to, mockContract)
+	}
+	if tx.Nonce() != 0 {
+		t.Errorf("nonce = %d, want 0", tx.Nonce())
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Errorf("type = %d, want dynamic fee", tx.Type())
+	}
+	if tx.ChainId().Cmp(m.chainID) != 0 {
+		t.Errorf("chain id = %s, want %s", tx.ChainId(), m.chainID)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(m.chainID), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); from != want {
+		t.Errorf("signed by %s, want %s", from, want)
+	}
+	want, err := c.abi.Pack("set", big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tx.Data()) != string(want) {
+		t.Errorf("data = %x, want %x", tx.Data(), want)
+	}
+
+	got, err := c.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("Get = %s, want 42", got)
+	}
+}
+
+func TestSubUnderflow(t *testing.T) {
+	m := newMockBackend(t)
+	m.setValue(big.NewInt(5))
+	c, _ := newMockClient(t, m, Config{})
+
+	_, err := c.Sub(context.Background(), big.NewInt(6))
+	if !errors.Is(err, ErrUnderflow) {
+		t.Fatalf("Sub = %v, want ErrUnderflow", err)
+	}
+	if n := len(m.sentTxs()); n != 0 {
+		t.Errorf("sent %d transactions, want none", n)
+	}
+	if got := m.stored(); got.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("stored = %s, want 5", got)
+	}
+}
+
+func TestSendFailureReleasesNonce(t *testing.T) {
+	ctx := context.Background()
+	m := newMockBackend(t)
+	c, _ := newMockClient(t, m, Config{})
+
+	refused := errors.New("refused")
+	m.failWith("SendTransaction", refused)
+	if _, err := c.Add(ctx, big.NewInt(1)); !errors.Is(err, refused) {
+		t.Fatalf("Add = %v, want %v", err, refused)
+	}
+
+	m.failWith("SendTransaction", nil)
+	if _, err := c.Add(ctx, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	sent := m.sentTxs()
+	if len(sent) != 1 || sent[0].Nonce() != 0 {
+		t.Fatalf("sent %d transactions, want one with nonce 0", len(sent))
+	}
+}
+
+func TestSetBatchChecksValuesFirst(t *testing.T) {
+	ctx := context.Background()
+	m := newMockBackend(t)
+	c, _ := newMockClient(t, m, Config{})
+
+	_, err := c.SetBatch(ctx, []*big.Int{big.NewInt(1), big.NewInt(-2), big.NewInt(3)})
+	if !errors.Is(err, ErrValueOutOfRange) {
+		t.Fatalf("SetBatch = %v, want ErrValueOutOfRange", err)
+	}
+	if n := len(m.sentTxs()); n != 0 {
+		t.Fatalf("sent %d transactions, want none", n)
+	}
+
+	// No nonce was reserved, so the next transaction still uses 0.
+	if _, err := c.Set(ctx, big.NewInt(4)); err != nil {
+		t.Fatal(err)
+	}
+	if sent := m.sentTxs(); len(sent) != 1 || sent[0].Nonce() != 0 {
+		t.Errorf("sent %d transactions, want one with nonce 0", len(sent))
+	}
+}
+
+// TestConcurrentGetSet shares one client between goroutines reading and
+// writing at once, as the server does.  Run it with -race.
+func TestConcurrentGetSet(t *testing.T) {
+	const workers, rounds = 8, 5
+	ctx := context.Background()
+	m := newMockBackend(t)
+	c, _ := newMockClient(t, m, Config{})
+
+	var wg sync.WaitGroup
+	errc := make(chan error, 2*workers*rounds)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				if _, err := c.Get(ctx); err != nil {
+					errc <- err
+				}
+				if _, err := c.Set(ctx, big.NewInt(int64(w*rounds+r+1))); err != nil {
+					errc <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		t.Error(err)
+	}
+
+	// Every Set got a nonce of its own, with no gaps.
+	sent := m.sentTxs()
+	if len(sent) != workers*rounds {
+		t.Fatalf("sent %d transactions, want %d", len(sent), workers*rounds)
+	}
+	byNonce := make(map[uint64]*types.Transaction)
+	for _, tx := range sent {
+		if byNonce[tx.Nonce()] != nil {
+			t.Errorf("nonce %d sent twice", tx.Nonce())
+		}
+		byNonce[tx.Nonce()] = tx
+	}
+	for nonce := range uint64(len(sent)) {
+		if byNonce[nonce] == nil {
+			t.Fatalf("nonce %d never sent", nonce)
+		}
+	}
+	last := byNonce[uint64(len(sent)-1)]
+
+	// The value left is the one set by the last nonce mined.
+	args, err := c.abi.Methods["set"].Inputs.Unpack(last.Data()[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := args[0].(*big.Int); got.Cmp(want) != 0 {
+		t.Errorf("Get = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,568 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/history"
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+)
+
+// Config holds everything needed to construct a StorageClient.
+type Config struct {
+	RPCURL          string
+	WSURL           string // websocket endpoint for subscriptions, if RPCURL is http
+	ContractAddress string
+	PrivateKey      string // hex encoded, without 0x prefix
+
+	// ChainID, if set, is used for signing instead of asking the node.
+	ChainID *big.Int
+
+	// ExpectedChainID, if set, makes the client check the node's chain ID
+	// before signing and refuse to send to any other chain.
+	ExpectedChainID *big.Int
+
+	// ExpectedCodeHash, if set, is the keccak256 hash of the runtime code
+	// VerifyBytecode expects at ContractAddress.  It overrides the hash
+	// of the runtime code embedded in this package.
+	ExpectedCodeHash string
+
+	// KeystorePath and KeystorePassword select an encrypted JSON keystore
+	// to sign with.  They take precedence over PrivateKey.  KeystorePath
+	// may also be a directory of keystore files, as geth keeps, from which
+	// the file for SignerAddress, or else the AccountIndex-th by name, is
+	// used.
+	KeystorePath     string
+	KeystorePassword string
+
+	// Mnemonic, if set, is a BIP-39 seed phrase the signing key is derived
+	// from along DerivationPath (default m/44'/60'/0'/0/0), with
+	// AccountIndex added to the path's last component so one seed can
+	// back several senders.  With SignerAddress set, the first 100
+	// accounts are searched for it instead.  It takes precedence over
+	// PrivateKey.
+	Mnemonic           string
+	MnemonicPassphrase string
+	DerivationPath     string
+	AccountIndex       uint32
+
+	// ReadOnly builds a client with no key material, ignoring every
+	// signing setting.  Reads and event watching work as usual; methods
+	// that send a transaction fail with ErrReadOnly.
+	ReadOnly bool
+
+	// Signer selects where transactions are signed.  "hardware" uses the
+	// first Ledger or Trezor plugged in, at DerivationPath and
+	// AccountIndex, and asks for each transaction to be approved on the
+	// device within SignerTimeout, two minutes by default.  Empty selects
+	// SignerURL or a local key.
+	Signer        string
+	SignerTimeout time.Duration
+
+	// SignerURL, if set, points at an external signer such as clef that
+	// signs transactions for SignerAddress, or for the first account it
+	// lists.  It takes precedence over every local key, which must
+	// otherwise belong to SignerAddress if that is set.
+	SignerURL     string
+	SignerAddress string
+
+	FeeMode     FeeMode  // also selects the transaction type: legacy, accesslist or dynamic
+	GasPrice    *big.Int // wei; if set, used as a legacy gas price instead of FeeMode
+	ContractBin string   // path to the compiled contract, used by Deploy
+
+	// GasPriceTTL, if set, caches the node's suggested gas price and tip
+	// cap for that long and refreshes them in the background, so pricing
+	// a transaction does not wait on the node.  Meant for long-running
+	// processes such as the server.
+	GasPriceTTL time.Duration
+
+	// GasOracle, if set, suggests the gas price and tip cap transactions
+	// are priced with in place of the node, which is still asked when
+	// the oracle fails.  GasOracleURL sets up an HTTPGasOracle for
+	// GasTier, standard by default, when GasOracle is not set.
+	GasOracle    GasOracle
+	GasOracleURL string
+	GasTier      GasTier
+
+	// RPCPoolSize, if above one, spreads requests over up to that many
+	// connections to RPCURL, opened as existing ones become busy.
+	RPCPoolSize int
+
+	// RPCHeaders are sent with every request to the node, such as an
+	// Authorization header for a provider that needs one.  Their values
+	// are masked when the Config is printed or logged.
+	RPCHeaders http.Header
+
+	// ABIFile, if set, is a JSON contract ABI used by Call and Transact in
+	// place of the generated binding's, so methods added to the contract
+	// can be used without regenerating it.  Methods it leaves out are
+	// taken from the generated ABI.
+	ABIFile string
+
+	// AutoDeploy, for development chains only, makes
+	// NewStorageClientFromConfig deploy a fresh contract when
+	// ContractAddress is unset or has no code, and remember its address in
+	// DeployStateFile for the next run.  See bindOrDeploy.
+	AutoDeploy      bool
+	DeployStateFile string
+
+	// Relay makes Relay send the transactions users sign for the client's
+	// account to pay for.  RelayNonceFile records each user's next nonce,
+	// so a request cannot be relayed twice.
+	Relay          bool
+	RelayNonceFile string
+
+	// Confirmations is the number of blocks that must be built on top of
+	// a transaction's block before Set and Add return.
+	Confirmations uint64
+
+	// PollInterval is how often the node is asked for the receipt of a
+	// pending transaction.  It defaults to one second.
+	PollInterval time.Duration
+
+	// ReceiptCacheSize and ReceiptCacheTTL bound the in-memory cache of
+	// mined receipts.  They default to 256 entries and five minutes; a
+	// negative size disables the cache.
+	ReceiptCacheSize int
+	ReceiptCacheTTL  time.Duration
+
+	// MiningTimeout, if set, is how long to wait for a transaction before
+	// resubmitting it with fees raised by GasBumpPercent, at most
+	// MaxGasBumps times.
+	MiningTimeout  time.Duration
+	GasBumpPercent uint64
+	MaxGasBumps    int
+
+	// GasBuffer and GasBufferPercent pad every gas estimate, by a flat
+	// amount and by a percentage of the estimate respectively.  When
+	// neither is set a flat buffer of 20000 is used.
+	GasBuffer        uint64
+	GasBufferPercent uint64
+
+	// DefaultGasLimit is the limit placed on transaction options before
+	// an estimate replaces it.
+	DefaultGasLimit uint64
+
+	// GasEstimateFallback, if set, sends a transaction whose gas estimate
+	// failed anyway, with DefaultGasLimit, instead of failing.  Nodes
+	// refuse to estimate a transaction that would revert in the current
+	// state, even if it will succeed once pending transactions land.
+	GasEstimateFallback bool
+
+	// MinValue and MaxValue, if set, bound the argument of Set, Add and
+	// Sub.  Arguments outside them are rejected with ErrValueOutOfRange.
+	MinValue *big.Int
+	MaxValue *big.Int
+
+	// SkipIfUnchanged makes Set skip the transaction when the contract
+	// already holds the value being set.  See Set for the caveat.
+	SkipIfUnchanged bool
+
+	// SkipBalanceCheck disables the check that the sender can afford a
+	// transaction before it is submitted, saving an RPC call.
+	SkipBalanceCheck bool
+
+	// DryRun makes Set and Add estimate and simulate their transaction
+	// without submitting it.
+	DryRun bool
+
+	// Simulate makes every transaction first run as an eth_call against
+	// the pending state, and not be sent if that reverts, so a doomed
+	// transaction fails with its revert reason instead of costing gas.
+	// It costs an extra call per transaction.
+	Simulate bool
+
+	// StrictCommit makes Commit apply the deltas buffered by
+	// AccumulateAdd with an on-chain Add rather than a Set of the value it
+	// read, so writes by others in between are not lost.
+	StrictCommit bool
+
+	// NoWait makes Set, Add, Sub and SetBatch return as soon as their
+	// transaction is submitted, with a result that only has TxHash and
+	// Pending set, instead of waiting for it to be mined.
+	NoWait bool
+
+	// CallTimeout bounds each request to the node.  It defaults to 30s;
+	// a negative value disables it.  WithCallTimeout overrides it for a
+	// single operation.
+	CallTimeout time.Duration
+
+	// RateLimit, if set, caps the requests per second sent to the node,
+	// in bursts of up to RateBurst requests.  Requests over the limit
+	// wait for their turn.  RateBurst defaults to RateLimit rounded up.
+	RateLimit float64
+	RateBurst int
+
+	// RetryAttempts and RetryBackoff control how transient RPC failures
+	// are retried.  Zero values select the defaults.
+	RetryAttempts int
+	RetryBackoff  time.Duration
+
+	// BreakerThreshold, if set, enables a circuit breaker that stops
+	// sending requests for BreakerCooldown once the node has failed
+	// BreakerThreshold times in a row within BreakerWindow.  The window
+	// and cooldown default to 30s each.
+	BreakerThreshold int
+	BreakerWindow    time.Duration
+	BreakerCooldown  time.Duration
+
+	// LogChunkSize is the number of blocks covered by each log query made
+	// by FilterValueChanged.
+	LogChunkSize uint64
+
+	// DeployBlock is the block the contract was deployed in, if known.
+	// Log scans that look for the contract's whole history, such as
+	// IsInitialized, start there instead of at genesis.
+	DeployBlock uint64
+
+	// MulticallAddress is the address of a Multicall3 contract, used by
+	// Registry.GetAll to read every contract in one call.  Most chains
+	// have one at 0xcA11bde05977b3631167028862bE2a173976CA11.  If unset,
+	// GetAll reads the contracts one by one.
+	MulticallAddress string
+
+	// Metrics, if set, records call counts, latencies and gas usage.
+	Metrics *metrics.Metrics
+
+	// TxStore, if set, receives a record of every transaction once it
+	// has been mined or has failed.
+	TxStore history.Store
+
+	// IdempotencyTTL is how long a completed transaction is remembered
+	// under its idempotency key.  It defaults to ten minutes.  See
+	// WithIdempotencyKey.
+	IdempotencyTTL time.Duration
+
+	// Logger receives the client's diagnostic output.  It defaults to
+	// slog.Default().
+	Logger *slog.Logger
+}
+
+// ConfigFromEnv reads a Config from the process environment.
+func ConfigFromEnv() (Config, error) {
+	return configFromLookup(os.Getenv)
+}
+
+// configFromLookup reads a Config from the settings getenv returns, by
+// environment variable name.
+func configFromLookup(getenv func(string) string) (Config, error) {
+	feeMode, err := ParseFeeMode(getenv("FEE_MODE"))
+	if err != nil {
+		return Config{}, err
+	}
+	gasTier, err := ParseGasTier(getenv("GAS_TIER"))
+	if err != nil {
+		return Config{}, fmt.Errorf("parsing GAS_TIER: %w", err)
+	}
+	cfg := Config{
+		RPCURL:             getenv("RPC_URL"),
+		WSURL:              getenv("WS_URL"),
+		ContractAddress:    getenv("CONTRACT_ADDRESS"),
+		PrivateKey:         getenv("PRIVATE_KEY"),
+		KeystorePath:       getenv("KEYSTORE_PATH"),
+		KeystorePassword:   getenv("KEYSTORE_PASSWORD"),
+		Signer:             getenv("SIGNER"),
+		SignerURL:          getenv("SIGNER_URL"),
+		SignerAddress:      getenv("SIGNER_ADDRESS"),
+		Mnemonic:           getenv("MNEMONIC"),
+		MnemonicPassphrase: getenv("MNEMONIC_PASSPHRASE"),
+		DerivationPath:     getenv("DERIVATION_PATH"),
+		FeeMode:            feeMode,
+		GasOracleURL:       getenv("GAS_ORACLE_URL"),
+		GasTier:            gasTier,
+		ContractBin:        getenv("CONTRACT_BIN"),
+		ABIFile:            getenv("ABI_FILE"),
+		MulticallAddress:   getenv("MULTICALL_ADDRESS"),
+		DeployStateFile:    getenv("DEPLOY_STATE_FILE"),
+		RelayNonceFile:     getenv("RELAY_NONCE_FILE"),
+		ExpectedCodeHash:   getenv("EXPECTED_CODE_HASH"),
+	}
+	if v := getenv("TX_HISTORY_FILE"); v != "" {
+		cfg.TxStore = history.NewFileStore(v)
+	}
+	if v := getenv("ACCOUNT_INDEX"); v != "" {
+		index, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return Config{}, fmt.Errorf("parsing ACCOUNT_INDEX: %w", err)
+		}
+		cfg.AccountIndex = uint32(index)
+	}
+	if v := getenv("CHAIN_ID"); v != "" {
+		id, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return Config{}, fmt.Errorf("parsing CHAIN_ID: invalid number %q", v)
+		}
+		cfg.ChainID = id
+	}
+	if v := getenv("GAS_PRICE"); v != "" {
+		if cfg.GasPrice, err = ParseGwei(v); err != nil {
+			return Config{}, fmt.Errorf("parsing GAS_PRICE: %w", err)
+		}
+	}
+	if v := getenv("EXPECTED_CHAIN_ID"); v != "" {
+		id, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return Config{}, fmt.Errorf("parsing EXPECTED_CHAIN_ID: invalid number %q", v)
+		}
+		cfg.ExpectedChainID = id
+	}
+	if v := getenv("CONFIRMATIONS"); v != "" {
+		if cfg.Confirmations, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing CONFIRMATIONS: %w", err)
+		}
+	}
+	if v := getenv("POLL_INTERVAL"); v != "" {
+		if cfg.PollInterval, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing POLL_INTERVAL: %w", err)
+		}
+	}
+	if v := getenv("RECEIPT_CACHE_SIZE"); v != "" {
+		if cfg.ReceiptCacheSize, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RECEIPT_CACHE_SIZE: %w", err)
+		}
+	}
+	if v := getenv("RECEIPT_CACHE_TTL"); v != "" {
+		if cfg.ReceiptCacheTTL, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RECEIPT_CACHE_TTL: %w", err)
+		}
+	}
+	if v := getenv("MINING_TIMEOUT"); v != "" {
+		if cfg.MiningTimeout, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing MINING_TIMEOUT: %w", err)
+		}
+	}
+	if v := getenv("GAS_BUMP_PERCENT"); v != "" {
+		if cfg.GasBumpPercent, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing GAS_BUMP_PERCENT: %w", err)
+		}
+	}
+	if v := getenv("MAX_GAS_BUMPS"); v != "" {
+		if cfg.MaxGasBumps, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing MAX_GAS_BUMPS: %w", err)
+		}
+	}
+	if v := getenv("GAS_BUFFER"); v != "" {
+		if cfg.GasBuffer, cfg.GasBufferPercent, err = parseGasBuffer(v); err != nil {
+			return Config{}, fmt.Errorf("parsing GAS_BUFFER: %w", err)
+		}
+	}
+	if v := getenv("DEFAULT_GAS_LIMIT"); v != "" {
+		if cfg.DefaultGasLimit, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing DEFAULT_GAS_LIMIT: %w", err)
+		}
+	}
+	if v := getenv("GAS_ESTIMATE_FALLBACK"); v != "" {
+		if cfg.GasEstimateFallback, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing GAS_ESTIMATE_FALLBACK: %w", err)
+		}
+	}
+	if v := getenv("DRY_RUN"); v != "" {
+		if cfg.DryRun, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing DRY_RUN: %w", err)
+		}
+	}
+	if v := getenv("RPC_POOL_SIZE"); v != "" {
+		if cfg.RPCPoolSize, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_POOL_SIZE: %w", err)
+		}
+	}
+	if v := getenv("RPC_HEADERS"); v != "" {
+		if cfg.RPCHeaders, err = ParseHeaders(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_HEADERS: %w", err)
+		}
+	}
+	if v := getenv("SIMULATE"); v != "" {
+		if cfg.Simulate, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing SIMULATE: %w", err)
+		}
+	}
+	if v := getenv("GAS_PRICE_TTL"); v != "" {
+		if cfg.GasPriceTTL, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing GAS_PRICE_TTL: %w", err)
+		}
+	}
+	if v := getenv("SIGNER_TIMEOUT"); v != "" {
+		if cfg.SignerTimeout, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing SIGNER_TIMEOUT: %w", err)
+		}
+	}
+	if v := getenv("READ_ONLY"); v != "" {
+		if cfg.ReadOnly, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing READ_ONLY: %w", err)
+		}
+	}
+	if v := getenv("STRICT_COMMIT"); v != "" {
+		if cfg.StrictCommit, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing STRICT_COMMIT: %w", err)
+		}
+	}
+	if v := getenv("NO_WAIT"); v != "" {
+		if cfg.NoWait, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing NO_WAIT: %w", err)
+		}
+	}
+	if v := getenv("SKIP_IF_UNCHANGED"); v != "" {
+		if cfg.SkipIfUnchanged, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing SKIP_IF_UNCHANGED: %w", err)
+		}
+	}
+	if v := getenv("AUTO_DEPLOY"); v != "" {
+		if cfg.AutoDeploy, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing AUTO_DEPLOY: %w", err)
+		}
+	}
+	if v := getenv("RELAY"); v != "" {
+		if cfg.Relay, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RELAY: %w", err)
+		}
+	}
+	if v := getenv("SKIP_BALANCE_CHECK"); v != "" {
+		if cfg.SkipBalanceCheck, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("parsing SKIP_BALANCE_CHECK: %w", err)
+		}
+	}
+	if v := getenv("LOG_CHUNK_SIZE"); v != "" {
+		if cfg.LogChunkSize, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing LOG_CHUNK_SIZE: %w", err)
+		}
+	}
+	if v := getenv("DEPLOY_BLOCK"); v != "" {
+		if cfg.DeployBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing DEPLOY_BLOCK: %w", err)
+		}
+	}
+	if v := getenv("RPC_TIMEOUT"); v != "" {
+		if cfg.CallTimeout, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_TIMEOUT: %w", err)
+		}
+	}
+	if v := getenv("RPC_RATE_LIMIT"); v != "" {
+		if cfg.RateLimit, err = strconv.ParseFloat(v, 64); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_RATE_LIMIT: %w", err)
+		}
+	}
+	if v := getenv("RPC_RATE_BURST"); v != "" {
+		if cfg.RateBurst, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_RATE_BURST: %w", err)
+		}
+	}
+	if v := getenv("RETRY_ATTEMPTS"); v != "" {
+		if cfg.RetryAttempts, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RETRY_ATTEMPTS: %w", err)
+		}
+	}
+	if v := getenv("RETRY_BACKOFF"); v != "" {
+		if cfg.RetryBackoff, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RETRY_BACKOFF: %w", err)
+		}
+	}
+	if v := getenv("IDEMPOTENCY_TTL"); v != "" {
+		if cfg.IdempotencyTTL, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing IDEMPOTENCY_TTL: %w", err)
+		}
+	}
+	if v := getenv("MIN_VALUE"); v != "" {
+		if cfg.MinValue, err = ParseValue(v); err != nil {
+			return Config{}, fmt.Errorf("parsing MIN_VALUE: %w", err)
+		}
+	}
+	if v := getenv("MAX_VALUE"); v != "" {
+		if cfg.MaxValue, err = ParseValue(v); err != nil {
+			return Config{}, fmt.Errorf("parsing MAX_VALUE: %w", err)
+		}
+	}
+	if v := getenv("RPC_BREAKER_THRESHOLD"); v != "" {
+		if cfg.BreakerThreshold, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_BREAKER_THRESHOLD: %w", err)
+		}
+	}
+	if v := getenv("RPC_BREAKER_WINDOW"); v != "" {
+		if cfg.BreakerWindow, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_BREAKER_WINDOW: %w", err)
+		}
+	}
+	if v := getenv("RPC_BREAKER_COOLDOWN"); v != "" {
+		if cfg.BreakerCooldown, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("parsing RPC_BREAKER_COOLDOWN: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// parseGasBuffer parses a gas buffer given either as a flat amount of gas
+// ("20000") or as a percentage of the estimate ("20%").
+func parseGasBuffer(s string) (flat, percent uint64, err error) {
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		percent, err = strconv.ParseUint(strings.TrimSpace(p), 10, 64)
+		return 0, percent, err
+	}
+	flat, err = strconv.ParseUint(s, 10, 64)
+	return flat, 0, err
+}
+
+// setDefaults fills in zero-valued optional settings.
+func (cfg *Config) setDefaults() {
+	if cfg.FeeMode == "" {
+		cfg.FeeMode = FeeModeDynamic
+	}
+	if cfg.GasTier == "" {
+		cfg.GasTier = GasTierStandard
+	}
+	if cfg.CallTimeout == 0 {
+		cfg.CallTimeout = defaultCallTimeout
+	}
+	if cfg.RetryAttempts == 0 {
+		cfg.RetryAttempts = defaultRetryAttempts
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.BreakerWindow == 0 {
+		cfg.BreakerWindow = defaultBreakerWindow
+	}
+	if cfg.BreakerCooldown == 0 {
+		cfg.BreakerCooldown = defaultBreakerCooldown
+	}
+	if cfg.GasBumpPercent == 0 {
+		cfg.GasBumpPercent = minGasBumpPercent
+	}
+	if cfg.MaxGasBumps == 0 {
+		cfg.MaxGasBumps = defaultMaxGasBumps
+	}
+	if cfg.GasBuffer == 0 && cfg.GasBufferPercent == 0 {
+		cfg.GasBuffer = defaultGasBuffer
+	}
+	if cfg.DefaultGasLimit == 0 {
+		cfg.DefaultGasLimit = defaultGasLimit
+	}
+	if cfg.ReceiptCacheSize == 0 {
+		cfg.ReceiptCacheSize = defaultReceiptCacheSize
+	}
+	if cfg.ReceiptCacheTTL == 0 {
+		cfg.ReceiptCacheTTL = defaultReceiptCacheTTL
+	}
+	if cfg.IdempotencyTTL == 0 {
+		cfg.IdempotencyTTL = defaultIdempotencyTTL
+	}
+	if cfg.SignerTimeout == 0 {
+		cfg.SignerTimeout = defaultSignerTimeout
+	}
+	if cfg.DeployStateFile == "" {
+		cfg.DeployStateFile = defaultDeployStateFile
+	}
+	if cfg.RelayNonceFile == "" {
+		cfg.RelayNonceFile = defaultRelayNonceFile
+	}
+	if cfg.LogChunkSize == 0 {
+		cfg.LogChunkSize = defaultLogChunkSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+}
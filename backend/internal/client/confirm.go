@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// defaultPollInterval is how often WaitConfirmed checks for progress when no
+// interval is given.
+const defaultPollInterval = time.Second
+
+// ErrReorged is returned by WaitConfirmed when a mined transaction drops
+// out of the canonical chain before reaching the requested depth.
+var ErrReorged = errors.New("transaction removed by chain reorganisation")
+
+// ErrMiningTimeout is returned when the context's deadline passes, or
+// cfg.MiningTimeout runs out with no gas bumps left, before a transaction
+// is confirmed.  The transaction may still be mined: the error is a
+// *MiningTimeoutError carrying its hash, and comes with a pending
+// TxResult, so callers can keep waiting, bump its fees or report it.
+var ErrMiningTimeout = errors.New("transaction not mined in time")
+
+// MiningTimeoutError reports the transaction a wait timed out on.  It
+// matches ErrMiningTimeout under errors.Is.
+type MiningTimeoutError struct {
+	TxHash common.Hash // the last version sent, if its fees were bumped
+
+	err error // the context's error
+}
+
+func (e *MiningTimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s, it may still be mined", ErrMiningTimeout, e.TxHash.Hex())
+}
+
+func (e *MiningTimeoutError) Is(target error) bool { return target == ErrMiningTimeout }
+
+func (e *MiningTimeoutError) Unwrap() error { return e.err }
+
+// ReceiptBackend is what WaitConfirmed needs from the node connection.
+// *jumboclient.Client satisfies it.
+type ReceiptBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// WaitConfirmed waits until tx has been mined and buried under
+// confirmations further blocks, then returns its receipt.  With zero
+// confirmations it returns as soon as the transaction is mined.  If the
+// transaction is seen in a block and later disappears, the last receipt
+// seen is returned together with ErrReorged.
+//
+// The node is polled every pollInterval, or every second if pollInterval
+// is zero.  A transaction the node does not know yet is still pending.
+func WaitConfirmed(ctx context.Context, b ReceiptBackend, tx *types.Transaction, confirmations uint64, pollInterval time.Duration) (*types.Receipt, error) {
+	return waitConfirmedAny(ctx, b, []*types.Transaction{tx}, confirmations, pollInterval)
+}
+
+// waitConfirmedAny is WaitConfirmed for several versions of a transaction
+// sharing a nonce, returning the receipt of whichever is mined.  Once one
+// is seen in a block only that one is followed.
+func waitConfirmedAny(ctx context.Context, b ReceiptBackend, txs []*types.Transaction, confirmations uint64, pollInterval time.Duration) (*types.Receipt, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var seen *types.Receipt
+	for {
+		hashes := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		if seen != nil {
+			hashes = []common.Hash{seen.TxHash}
+		}
+		for _, hash := range hashes {
+			receipt, err := b.TransactionReceipt(ctx, hash)
+			switch {
+			case err == nil && receipt != nil:
+				seen = receipt
+				if confirmations == 0 {
+					return receipt, nil
+				}
+				head, err := b.BlockNumber(ctx)
+				if err == nil && head >= receipt.BlockNumber.Uint64()+confirmations {
+					return receipt, nil
+				}
+			case errors.Is(err, jumbochain.NotFound):
+				if seen != nil {
+					return seen, ErrReorged
+				}
+			}
+			// Other errors are treated as transient and polling continues.
+			if seen != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
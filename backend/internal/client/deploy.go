@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// defaultContractBin is where `solc --bin` writes the compiled contract
+// when run from the repository root.
+const defaultContractBin = "../build/SimpleStorage.bin"
+
+// LoadBytecode reads hex encoded contract creation code from path, as
+// written by `solc --bin`.
+func LoadBytecode(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	code, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(raw)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decoding bytecode in %s: %w", path, err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%s contains no bytecode", path)
+	}
+	return code, nil
+}
+
+// DeployStorage deploys a new SimpleStorage contract holding initVal and
+// waits for the deployment to be mined.  bytecode is the contract creation
+// code.
+func DeployStorage(ctx context.Context, backend DeployBackend, auth *bind.TransactOpts, bytecode []byte, initVal *big.Int) (common.Address, *storage.Storage, error) {
+	return deployStorage(ctx, backend, auth, bytecode, initVal, 0)
+}
+
+// deployStorage is DeployStorage polling for the receipt every
+// pollInterval.
+func deployStorage(ctx context.Context, backend DeployBackend, auth *bind.TransactOpts, bytecode []byte, initVal *big.Int, pollInterval time.Duration) (common.Address, *storage.Storage, error) {
+	parsed, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	address, tx, _, err := bind.DeployContract(auth, *parsed, bytecode, backend, initVal)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("sending deployment: %w", err)
+	}
+	receipt, err := WaitConfirmed(ctx, backend, tx, 0, pollInterval)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("deployment %s failed: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, nil, fmt.Errorf("deployment %s failed: %w", tx.Hash().Hex(), &RevertError{})
+	}
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("reading deployed code: %w", err)
+	}
+	if len(code) == 0 {
+		return common.Address{}, nil, fmt.Errorf("deployment %s failed: %w %s", tx.Hash().Hex(), ErrContractNotDeployed, address.Hex())
+	}
+
+	instance, err := storage.NewStorage(address, backend)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return address, instance, nil
+}
+
+// EstimateDeploy predicts what deploying bytecode with initVal from
+// auth.From would cost at the fees set on auth, without sending anything.
+// The gas limit is the estimate itself, as DeployStorage sends with.
+func EstimateDeploy(ctx context.Context, backend bind.ContractBackend, auth *bind.TransactOpts, bytecode []byte, initVal *big.Int) (*CostEstimate, error) {
+	parsed, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack("", initVal)
+	if err != nil {
+		return nil, err
+	}
+	value := auth.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	gas, err := backend.EstimateGas(ctx, jumbochain.CallMsg{
+		From:  auth.From,
+		Value: value,
+		Data:  append(append([]byte{}, bytecode...), input...),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("estimating deployment gas: %w", classifyNodeError(err))
+	}
+
+	price := auth.GasFeeCap
+	if price == nil {
+		price = auth.GasPrice
+	}
+	if price == nil {
+		return nil, fmt.Errorf("estimating deployment cost: no gas price set")
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gas), price)
+	cost.Add(cost, value)
+	return &CostEstimate{
+		Gas:          gas,
+		GasLimit:     gas,
+		MaxFeePerGas: price,
+		Value:        value,
+		MaxCost:      cost,
+	}, nil
+}
+
+// DeployBackend is what DeployStorage needs from the node connection.
+// *jumboclient.Client satisfies it.
+type DeployBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Deploy deploys a new SimpleStorage contract holding initVal using the
+// node and key from cfg, and returns a client bound to it.  The contract
+// code is read from cfg.ContractBin.
+func Deploy(ctx context.Context, cfg Config, initVal *big.Int) (*StorageClient, error) {
+	c, err := dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.deploy(ctx, initVal); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// EstimateDeployment predicts what Deploy would cost with the same cfg
+// and initVal, without sending anything.
+func EstimateDeployment(ctx context.Context, cfg Config, initVal *big.Int) (*CostEstimate, error) {
+	c, err := dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	bytecode, err := LoadBytecode(c.contractBin())
+	if err != nil {
+		return nil, err
+	}
+	auth := &bind.TransactOpts{Context: ctx, From: c.from}
+	if auth.Value, err = txValue(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.applyFees(ctx, auth); err != nil {
+		return nil, err
+	}
+	var estimate *CostEstimate
+	err = c.retry(ctx, func() (err error) {
+		estimate, err = EstimateDeploy(ctx, c.client, auth, bytecode, initVal)
+		return err
+	})
+	return estimate, err
+}
+
+// contractBin returns the path of the compiled contract.
+func (c *StorageClient) contractBin() string {
+	if c.cfg.ContractBin == "" {
+		return defaultContractBin
+	}
+	return c.cfg.ContractBin
+}
+
+// deploy deploys a new contract holding initVal from c's account and
+// binds c to it.
+func (c *StorageClient) deploy(ctx context.Context, initVal *big.Int) (common.Address, error) {
+	bytecode, err := LoadBytecode(c.contractBin())
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	auth, err := c.getTransactionAuthorizer(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	auth.GasLimit = 0 // let the binding estimate the creation cost
+
+	address, _, err := deployStorage(ctx, c.client, auth, bytecode, initVal, c.cfg.PollInterval)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return address, c.bind(address)
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+)
+
+// dryRun estimates and simulates method without submitting anything.
+func (c *StorageClient) dryRun(ctx context.Context, method string, args []interface{}) (*TxResult, error) {
+	gas, err := c.estimateGas(ctx, method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("estimating gas for %s: %w", method, err)
+	}
+	return c.simulate(ctx, method, c.gasLimit(gas), args)
+}
+
+// preflight executes method against the pending state with eth_call and
+// fails, with a *RevertError if it reverted, unless the call succeeds.
+// A gas of zero leaves the limit to the node.
+func (c *StorageClient) preflight(ctx context.Context, method string, gas uint64, args []interface{}) error {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	value, err := txValue(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.retry(ctx, func() error {
+		_, err := c.client.PendingCallContract(ctx, jumbochain.CallMsg{
+			From:  c.from,
+			To:    &c.address,
+			Gas:   gas,
+			Value: value,
+			Data:  data,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("simulating %s, not sent: %w", method, classifyNodeError(err))
+	}
+	c.cfg.Logger.DebugContext(ctx, "simulation succeeded", "method", method)
+	return nil
+}
+
+// simulate executes method against the latest state with eth_call instead
+// of submitting it, and reports the value the contract would hold
+// afterwards.  Methods that return the new value (add) report it; for those
+// that do not (set) the first argument is taken as the new value.
+func (c *StorageClient) simulate(ctx context.Context, method string, gas uint64, args []interface{}) (*TxResult, error) {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := txValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	err = c.retry(ctx, func() (err error) {
+		out, err = c.client.CallContract(ctx, jumbochain.CallMsg{
+			From:  c.from,
+			To:    &c.address,
+			Gas:   gas,
+			Value: value,
+			Data:  data,
+		}, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("simulating %s: %w", method, classifyNodeError(err))
+	}
+
+	var newValue *big.Int
+	if len(args) > 0 {
+		newValue, _ = args[0].(*big.Int)
+	}
+	results, err := c.abi.Unpack(method, out)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s result: %w", method, err)
+	}
+	if len(results) > 0 {
+		if v, ok := results[0].(*big.Int); ok {
+			newValue = v
+		}
+	}
+
+	return &TxResult{
+		DryRun:       true,
+		EstimatedGas: gas,
+		NewValue:     newValue,
+	}, nil
+}
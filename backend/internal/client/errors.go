@@ -0,0 +1,54 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNonceTooLow is returned when the node rejects a transaction because
+// its nonce has already been used, usually by a transaction sent with the
+// same key from somewhere else.  ErrNonceTooHigh is its counterpart for a
+// nonce that leaves a gap.  The client resyncs its nonce and resubmits
+// once, so these are only returned if the retry fails the same way.
+var (
+	ErrNonceTooLow  = errors.New("nonce too low")
+	ErrNonceTooHigh = errors.New("nonce too high")
+)
+
+// maxNonceResyncs is how many times submit resyncs the nonce and resends
+// a transaction the node rejected for its nonce.
+const maxNonceResyncs = 1
+
+// isNonceMismatch reports whether err is the node rejecting a nonce that
+// does not follow on from the account's last transaction.
+func isNonceMismatch(err error) bool {
+	return errors.Is(err, ErrNonceTooLow) || errors.Is(err, ErrNonceTooHigh)
+}
+
+// classifyNodeError maps an error returned by the node for a call,
+// estimate or transaction to the matching error of this package, keeping
+// the original in the chain.  Nodes report most rejections only as message
+// text, so matching is on the messages geth-derived nodes use.
+func classifyNodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if data := revertData(err); data != nil {
+		return &RevertError{Reason: decodeRevert(data), err: err}
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "execution reverted"):
+		return &RevertError{err: err}
+	case strings.Contains(msg, "nonce too low"):
+		return fmt.Errorf("%w: %w", ErrNonceTooLow, err)
+	case strings.Contains(msg, "nonce too high"):
+		return fmt.Errorf("%w: %w", ErrNonceTooHigh, err)
+	case strings.Contains(msg, "insufficient funds"):
+		return fmt.Errorf("%w: %w", ErrInsufficientFunds, err)
+	case strings.Contains(msg, "invalid chain id"):
+		return fmt.Errorf("%w: %w", ErrChainMismatch, err)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+)
+
+// CostEstimate is the predicted cost of a transaction, priced the way the
+// client would price it if sent now.
+type CostEstimate struct {
+	Gas      uint64 // gas the node estimates the call uses
+	GasLimit uint64 // Gas padded by the configured buffer
+
+	// MaxFeePerGas is the gas price, or under EIP-1559 the fee cap, so it
+	// is the most each unit of gas can cost.
+	MaxFeePerGas *big.Int
+
+	// Value is the native currency attached with WithTxValue.
+	Value *big.Int
+
+	// MaxCost is GasLimit * MaxFeePerGas + Value: the most the sender can
+	// be charged.  The actual cost is usually lower, since unused gas is
+	// refunded and the base fee is rarely at the cap.
+	MaxCost *big.Int
+}
+
+// EstimateCost predicts what calling method with args would cost, without
+// reserving a nonce or sending anything.
+func (c *StorageClient) EstimateCost(ctx context.Context, method string, args ...interface{}) (*CostEstimate, error) {
+	if _, ok := c.abi.Methods[method]; !ok {
+		return nil, fmt.Errorf("method %q not found in contract abi", method)
+	}
+	value, err := txValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := c.estimateGas(ctx, method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("estimating gas for %s: %w", method, err)
+	}
+
+	auth := &bind.TransactOpts{Context: ctx}
+	if err := c.applyFees(ctx, auth); err != nil {
+		return nil, err
+	}
+	price := auth.GasFeeCap
+	if price == nil {
+		price = auth.GasPrice
+	}
+
+	limit := c.gasLimit(gas)
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(limit), price)
+	cost.Add(cost, value)
+	return &CostEstimate{
+		Gas:          gas,
+		GasLimit:     limit,
+		MaxFeePerGas: price,
+		Value:        value,
+		MaxCost:      cost,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+const (
+	resubscribeBackoff    = time.Second
+	maxResubscribeBackoff = 30 * time.Second
+
+	// defaultLogChunkSize keeps log queries within the range most hosted
+	// providers accept.
+	defaultLogChunkSize = 2000
+)
+
+// ValueChanged is a decoded ValueChanged event emitted by the contract.
+type ValueChanged struct {
+	Setter      common.Address
+	OldValue    *big.Int
+	NewValue    *big.Int
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+
+	// Removed is set when a chain reorganisation has dropped the block
+	// of an event delivered earlier.  The event is repeated with Removed
+	// set so consumers can undo it.
+	Removed bool
+}
+
+func newValueChanged(ev *storage.StorageValueChanged) *ValueChanged {
+	return &ValueChanged{
+		Setter:      ev.Setter,
+		OldValue:    ev.OldValue,
+		NewValue:    ev.NewValue,
+		BlockNumber: ev.Raw.BlockNumber,
+		BlockHash:   ev.Raw.BlockHash,
+		TxHash:      ev.Raw.TxHash,
+		LogIndex:    ev.Raw.Index,
+		Removed:     ev.Raw.Removed,
+	}
+}
+
+// WatchValueChanged streams ValueChanged events to sink until ctx is
+// cancelled.  Subscriptions need a websocket or IPC connection: cfg.WSURL
+// if set, otherwise cfg.RPCURL.  If the subscription fails it is
+// re-established with backoff; events emitted while disconnected are not
+// replayed.  If setters are given, only changes made by one of them are
+// streamed; the node filters on the indexed setter topic.  Events undone
+// by a reorg are sent again with Removed set, whether the node reports
+// the reorg or the watcher sees a block it delivered from replaced.
+// WatchValueChanged returns ctx.Err() once cancelled.
+func (c *StorageClient) WatchValueChanged(ctx context.Context, sink chan<- *ValueChanged, setters ...common.Address) error {
+	if c.ws == nil && isHTTP(c.cfg.RPCURL) {
+		return fmt.Errorf("%w: %s is an http endpoint, set WS_URL to a ws:// or wss:// url", ErrNoSubscriptions, redactURL(c.cfg.RPCURL))
+	}
+	backoff := resubscribeBackoff
+	reorgs := newReorgTracker()
+	for {
+		events := make(chan *storage.StorageValueChanged)
+		sub, err := c.watcher.WatchValueChanged(&bind.WatchOpts{Context: ctx}, events, setters)
+		if err != nil {
+			c.cfg.Logger.WarnContext(ctx, "subscribing to ValueChanged failed", "err", err, "retryIn", backoff)
+		} else {
+			backoff = resubscribeBackoff
+			err = forwardEvents(ctx, sub.Err(), events, reorgs, sink)
+			sub.Unsubscribe()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.cfg.Logger.WarnContext(ctx, "ValueChanged subscription dropped", "err", err, "retryIn", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxResubscribeBackoff)
+	}
+}
+
+// forwardEvents copies events to sink, with the removals reorgs finds,
+// until the subscription fails or ctx is cancelled.
+func forwardEvents(ctx context.Context, errc <-chan error, events <-chan *storage.StorageValueChanged, reorgs *reorgTracker, sink chan<- *ValueChanged) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			return err
+		case ev := <-events:
+			for _, out := range reorgs.observe(newValueChanged(ev)) {
+				select {
+				case sink <- out:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// FilterValueChanged returns the ValueChanged events emitted between
+// fromBlock and toBlock inclusive, oldest first.  A nil toBlock means the
+// latest block.  The range is queried in chunks of cfg.LogChunkSize blocks
+// to stay under provider limits on log queries.  Over large ranges,
+// StreamFilterValueChanged avoids holding every event in memory.  If
+// setters are given, only changes made by one of them are returned.
+func (c *StorageClient) FilterValueChanged(ctx context.Context, fromBlock uint64, toBlock *uint64, setters ...common.Address) ([]*ValueChanged, error) {
+	var events []*ValueChanged
+	err := c.eachChunk(ctx, fromBlock, toBlock, setters, func(chunk []*ValueChanged) error {
+		events = append(events, chunk...)
+		return nil
+	})
+	return events, err
+}
+
+// StreamFilterValueChanged is like FilterValueChanged but sends the events
+// to sink as each chunk of blocks is read, so only one chunk is held in
+// memory at a time.  It closes sink when it returns, which is once the
+// range is done, a query fails or ctx is cancelled.
+func (c *StorageClient) StreamFilterValueChanged(ctx context.Context, fromBlock uint64, toBlock *uint64, sink chan<- *ValueChanged, setters ...common.Address) error {
+	defer close(sink)
+	return c.eachChunk(ctx, fromBlock, toBlock, setters, func(chunk []*ValueChanged) error {
+		for _, ev := range chunk {
+			select {
+			case sink <- ev:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// eachChunk queries [fromBlock, toBlock] in chunks of cfg.LogChunkSize
+// blocks for changes by setters, or by anyone if there are none, and
+// passes the events of each, oldest first, to fn.
+func (c *StorageClient) eachChunk(ctx context.Context, fromBlock uint64, toBlock *uint64, setters []common.Address, fn func([]*ValueChanged) error) error {
+	end, err := c.resolveToBlock(ctx, toBlock)
+	if err != nil {
+		return err
+	}
+	for start := fromBlock; start <= end; {
+		stop := min(start+c.cfg.LogChunkSize-1, end)
+		var chunk []*ValueChanged
+		err := c.retry(ctx, func() (err error) {
+			chunk, err = c.filterValueChangedRange(ctx, start, stop, setters)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("filtering blocks %d-%d: %w", start, stop, err)
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		if stop == end {
+			break
+		}
+		start = stop + 1
+	}
+	return nil
+}
+
+// filterValueChangedRange runs a single log query over [start, stop].
+func (c *StorageClient) filterValueChangedRange(ctx context.Context, start, stop uint64, setters []common.Address) ([]*ValueChanged, error) {
+	it, err := c.instance.FilterValueChanged(&bind.FilterOpts{Start: start, End: &stop, Context: ctx}, setters)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var events []*ValueChanged
+	for it.Next() {
+		events = append(events, newValueChanged(it.Event))
+	}
+	return events, it.Error()
+}
+
+// resolveToBlock returns *toBlock, or the latest block number if it is nil.
+func (c *StorageClient) resolveToBlock(ctx context.Context, toBlock *uint64) (uint64, error) {
+	if toBlock != nil {
+		return *toBlock, nil
+	}
+	var latest uint64
+	err := c.retry(ctx, func() (err error) {
+		latest, err = c.client.BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetching latest block: %w", err)
+	}
+	return latest, nil
+}
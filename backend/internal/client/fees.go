@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// FeeMode selects how transaction fees are priced.
+type FeeMode string
+
+const (
+	// FeeModeDynamic prices transactions with an EIP-1559 fee cap and tip.
+	FeeModeDynamic FeeMode = "dynamic"
+	// FeeModeLegacy prices transactions with a single gas price.
+	FeeModeLegacy FeeMode = "legacy"
+	// FeeModeAccessList sends EIP-2930 transactions: a single gas price
+	// plus an access list, generated with eth_createAccessList unless
+	// one was given with WithAccessList.
+	FeeModeAccessList FeeMode = "accesslist"
+)
+
+// ParseFeeMode converts s into a FeeMode.  An empty string selects the
+// dynamic mode.
+func ParseFeeMode(s string) (FeeMode, error) {
+	switch FeeMode(s) {
+	case "", FeeModeDynamic:
+		return FeeModeDynamic, nil
+	case FeeModeLegacy:
+		return FeeModeLegacy, nil
+	case FeeModeAccessList:
+		return FeeModeAccessList, nil
+	default:
+		return "", fmt.Errorf("unknown fee mode %q", s)
+	}
+}
+
+// ParseGwei parses a gas price given in gwei, such as "30" or "1.5", into
+// wei.  The price must be positive and a whole number of wei.
+func ParseGwei(s string) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid gas price %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(1e9))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("gas price %s gwei is not a whole number of wei", s)
+	}
+	if r.Sign() <= 0 {
+		return nil, fmt.Errorf("gas price must be positive, got %s gwei", s)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// FormatGwei formats an amount of wei in gwei, without trailing zeros.
+func FormatGwei(wei *big.Int) string {
+	return formatUnits(wei, 9)
+}
+
+// FormatEther formats an amount of wei in ether, without trailing zeros.
+func FormatEther(wei *big.Int) string {
+	return formatUnits(wei, 18)
+}
+
+// formatUnits formats wei divided by 10^decimals exactly.
+func formatUnits(wei *big.Int, decimals int) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	s := new(big.Rat).SetFrac(wei, unit).FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// feeSource is the subset of the node API needed to price a transaction.
+type feeSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	GasOracle
+}
+
+// applyDynamicFees populates the EIP-1559 fee fields of auth from the
+// latest base fee and the node's suggested tip.  The fee cap leaves room for
+// the base fee to double before the transaction becomes unmineable.  If the
+// chain does not report a base fee it falls back to a legacy gas price.
+func applyDynamicFees(ctx context.Context, client feeSource, auth *bind.TransactOpts) error {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("fetching latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return applyLegacyFees(ctx, client, auth)
+	}
+
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching gas tip cap: %w", err)
+	}
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	auth.GasPrice = nil
+	auth.GasTipCap = tip
+	auth.GasFeeCap = feeCap
+	return nil
+}
+
+// applyFees prices auth according to the configured fee mode, unless a
+// fixed gas price overrides it.
+func (c *StorageClient) applyFees(ctx context.Context, auth *bind.TransactOpts) error {
+	switch {
+	case c.cfg.GasPrice != nil:
+		applyFixedGasPrice(ctx, c.fees(), auth, c.cfg.GasPrice, c.cfg.Logger)
+		return nil
+	case c.cfg.FeeMode == FeeModeLegacy, c.cfg.FeeMode == FeeModeAccessList:
+		return applyLegacyFees(ctx, c.fees(), auth)
+	default:
+		return applyDynamicFees(ctx, c.fees(), auth)
+	}
+}
+
+// applyFixedGasPrice sets the configured gas price on auth instead of
+// asking the node for one.  The node's suggestion is still fetched so that
+// an override likely to leave the transaction stuck can be flagged.
+func applyFixedGasPrice(ctx context.Context, client feeSource, auth *bind.TransactOpts, price *big.Int, logger *slog.Logger) {
+	// Copy so concurrent transactions never share the configured value.
+	auth.GasPrice = new(big.Int).Set(price)
+	auth.GasTipCap = nil
+	auth.GasFeeCap = nil
+
+	if suggested, err := client.SuggestGasPrice(ctx); err == nil && price.Cmp(suggested) < 0 {
+		logger.WarnContext(ctx, "gas price override is below the node's suggestion, the transaction may not be mined",
+			"gasPrice", price, "suggested", suggested)
+	}
+}
+
+// applyLegacyFees sets a single gas price on auth from the node's suggestion.
+func applyLegacyFees(ctx context.Context, client feeSource, auth *bind.TransactOpts) error {
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching gas price: %w", err)
+	}
+	auth.GasPrice = price
+	auth.GasTipCap = nil
+	auth.GasFeeCap = nil
+	return nil
+}
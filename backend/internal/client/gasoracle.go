@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// GasOracle recommends fees for transactions.  The node is the default
+// oracle, and any ContractBackend is one; set Config.GasOracle or
+// Config.GasOracleURL to price transactions from elsewhere.
+type GasOracle interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// GasTier selects how fast the fees an HTTP gas oracle recommends should
+// get a transaction mined.
+type GasTier string
+
+const (
+	GasTierSlow     GasTier = "slow"
+	GasTierStandard GasTier = "standard"
+	GasTierFast     GasTier = "fast"
+)
+
+// ParseGasTier converts s into a GasTier.  An empty string selects the
+// standard tier.
+func ParseGasTier(s string) (GasTier, error) {
+	switch GasTier(s) {
+	case "", GasTierStandard:
+		return GasTierStandard, nil
+	case GasTierSlow:
+		return GasTierSlow, nil
+	case GasTierFast:
+		return GasTierFast, nil
+	default:
+		return "", fmt.Errorf("unknown gas tier %q, want slow, standard or fast", s)
+	}
+}
+
+// gasOracleTimeout bounds a request to an HTTP gas oracle, after which
+// the node's suggestion is used instead.
+const gasOracleTimeout = 5 * time.Second
+
+// HTTPGasOracle fetches recommended fees from a URL answering GET requests
+// with a JSON object of gwei amounts per tier, as strings or numbers:
+//
+//	{
+//	  "slow":     {"gasPrice": "20", "maxPriorityFee": "1"},
+//	  "standard": {"gasPrice": "25", "maxPriorityFee": "1.5"},
+//	  "fast":     {"gasPrice": "32", "maxPriorityFee": "2"}
+//	}
+//
+// gasPrice prices legacy and access-list transactions, maxPriorityFee is
+// the tip of dynamic-fee ones.
+type HTTPGasOracle struct {
+	URL    string
+	Tier   GasTier
+	Client *http.Client // defaults to one with a five second timeout
+}
+
+// NewHTTPGasOracle returns an oracle fetching tier's fees from url.
+func NewHTTPGasOracle(url string, tier GasTier) *HTTPGasOracle {
+	return &HTTPGasOracle{URL: url, Tier: tier, Client: &http.Client{Timeout: gasOracleTimeout}}
+}
+
+// gasOracleFees is one tier of an HTTP gas oracle's response.
+type gasOracleFees struct {
+	GasPrice       json.Number `json:"gasPrice"`
+	MaxPriorityFee json.Number `json:"maxPriorityFee"`
+}
+
+func (o *HTTPGasOracle) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	fees, err := o.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.parse("gasPrice", fees.GasPrice)
+}
+
+func (o *HTTPGasOracle) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	fees, err := o.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.parse("maxPriorityFee", fees.MaxPriorityFee)
+}
+
+// fetch returns the oracle's current fees for o.Tier.
+func (o *HTTPGasOracle) fetch(ctx context.Context) (*gasOracleFees, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gas oracle: %w", redactURLError(err))
+	}
+	req.Header.Set("Accept", "application/json")
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: gasOracleTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gas oracle: %w", redactURLError(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gas oracle: %s", resp.Status)
+	}
+	var tiers map[GasTier]*gasOracleFees
+	if err := json.NewDecoder(resp.Body).Decode(&tiers); err != nil {
+		return nil, fmt.Errorf("gas oracle: decoding response: %w", err)
+	}
+	tier := o.Tier
+	if tier == "" {
+		tier = GasTierStandard
+	}
+	fees := tiers[tier]
+	if fees == nil {
+		return nil, fmt.Errorf("gas oracle: response has no %s tier", tier)
+	}
+	return fees, nil
+}
+
+// parse converts the gwei amount of field into wei.
+func (o *HTTPGasOracle) parse(field string, gwei json.Number) (*big.Int, error) {
+	if gwei == "" {
+		return nil, fmt.Errorf("gas oracle: %s tier has no %s", o.Tier, field)
+	}
+	wei, err := ParseGwei(gwei.String())
+	if err != nil {
+		return nil, fmt.Errorf("gas oracle: %s: %w", field, err)
+	}
+	return wei, nil
+}
+
+// oracleFees is a feeSource taking its suggestions from oracle, and from
+// the node when the oracle fails, so an unreachable oracle does not stop
+// transactions being sent.
+type oracleFees struct {
+	node   feeSource
+	oracle GasOracle
+	logger *slog.Logger
+}
+
+func (f *oracleFees) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return f.node.HeaderByNumber(ctx, number)
+}
+
+func (f *oracleFees) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return f.suggest(ctx, "gas price", f.oracle.SuggestGasPrice, f.node.SuggestGasPrice)
+}
+
+func (f *oracleFees) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return f.suggest(ctx, "gas tip cap", f.oracle.SuggestGasTipCap, f.node.SuggestGasTipCap)
+}
+
+func (f *oracleFees) suggest(ctx context.Context, what string, oracle, node func(context.Context) (*big.Int, error)) (*big.Int, error) {
+	value, err := oracle(ctx)
+	if err == nil {
+		return value, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	f.logger.WarnContext(ctx, "gas oracle failed, using the node's "+what, "err", err)
+	return node(ctx)
+}
+
+// feeSourceFor returns where a client over backend takes its fees from:
+// the configured gas oracle, falling back to the node, or the node alone.
+func feeSourceFor(cfg Config, backend feeSource) feeSource {
+	oracle := cfg.GasOracle
+	if oracle == nil && cfg.GasOracleURL != "" {
+		oracle = NewHTTPGasOracle(cfg.GasOracleURL, cfg.GasTier)
+	}
+	if oracle == nil {
+		return backend
+	}
+	return &oracleFees{node: backend, oracle: oracle, logger: cfg.Logger}
+}
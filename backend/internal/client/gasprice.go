@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// Gas price kinds, as labelled in metrics.
+const (
+	gasPriceKind = "price"
+	gasTipKind   = "tip"
+)
+
+// GasPriceSnapshot is the content of the gas price cache.  A nil price has
+// not been fetched yet.
+type GasPriceSnapshot struct {
+	GasPrice     *big.Int
+	GasPriceAge  time.Duration
+	GasTipCap    *big.Int
+	GasTipCapAge time.Duration
+}
+
+// gasPriceCache is a feeSource that remembers the node's suggested gas
+// price and tip cap for ttl, and refreshes them in the background while
+// run is active, so pricing a transaction rarely waits on the node.  A
+// value older than ttl is fetched again on demand.  Headers are not
+// cached: the base fee changes with every block.
+type gasPriceCache struct {
+	source  feeSource
+	ttl     time.Duration
+	metrics *metrics.Metrics
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	price   *big.Int
+	priceAt time.Time
+	tip     *big.Int
+	tipAt   time.Time
+}
+
+func newGasPriceCache(cfg Config, source feeSource) *gasPriceCache {
+	return &gasPriceCache{source: source, ttl: cfg.GasPriceTTL, metrics: cfg.Metrics, logger: cfg.Logger}
+}
+
+// run refreshes the cache every half ttl, so it is always fresh, until
+// ctx is cancelled.  Failures are logged; readers then fetch on demand
+// once the cached value has expired.
+func (g *gasPriceCache) run(ctx context.Context) {
+	ticker := time.NewTicker(g.ttl / 2)
+	defer ticker.Stop()
+	for {
+		if _, err := g.fetch(ctx, gasPriceKind); err != nil && ctx.Err() == nil {
+			g.logger.WarnContext(ctx, "refreshing gas price failed", "err", err)
+		}
+		// Chains without EIP-1559 do not suggest a tip.
+		if _, err := g.fetch(ctx, gasTipKind); err != nil && ctx.Err() == nil {
+			g.logger.DebugContext(ctx, "refreshing gas tip cap failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *gasPriceCache) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return g.source.HeaderByNumber(ctx, number)
+}
+
+func (g *gasPriceCache) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return g.get(ctx, gasPriceKind)
+}
+
+func (g *gasPriceCache) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return g.get(ctx, gasTipKind)
+}
+
+// get returns the cached value of kind, fetching it if it is missing or
+// older than ttl.
+func (g *gasPriceCache) get(ctx context.Context, kind string) (*big.Int, error) {
+	g.mu.Lock()
+	value, at := g.price, g.priceAt
+	if kind == gasTipKind {
+		value, at = g.tip, g.tipAt
+	}
+	g.mu.Unlock()
+	if value != nil && time.Since(at) < g.ttl {
+		return new(big.Int).Set(value), nil
+	}
+	return g.fetch(ctx, kind)
+}
+
+// fetch asks the node for the value of kind and caches it.
+func (g *gasPriceCache) fetch(ctx context.Context, kind string) (*big.Int, error) {
+	suggest := g.source.SuggestGasPrice
+	if kind == gasTipKind {
+		suggest = g.source.SuggestGasTipCap
+	}
+	value, err := suggest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	g.mu.Lock()
+	if kind == gasTipKind {
+		g.tip, g.tipAt = value, now
+	} else {
+		g.price, g.priceAt = value, now
+	}
+	g.mu.Unlock()
+	g.metrics.ObserveGasPrice(kind, value, now)
+	return new(big.Int).Set(value), nil
+}
+
+// snapshot returns copies of the cached values and their ages.
+func (g *gasPriceCache) snapshot() GasPriceSnapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var s GasPriceSnapshot
+	if g.price != nil {
+		s.GasPrice, s.GasPriceAge = new(big.Int).Set(g.price), time.Since(g.priceAt)
+	}
+	if g.tip != nil {
+		s.GasTipCap, s.GasTipCapAge = new(big.Int).Set(g.tip), time.Since(g.tipAt)
+	}
+	return s
+}
+
+// GasPriceCache reports the cached gas price and tip cap with their ages.
+// ok is false unless cfg.GasPriceTTL enables the cache.
+func (c *StorageClient) GasPriceCache() (snapshot GasPriceSnapshot, ok bool) {
+	if c.gasPrices == nil {
+		return GasPriceSnapshot{}, false
+	}
+	return c.gasPrices.snapshot(), true
+}
+
+// fees returns where transactions are priced from: the gas price cache if
+// enabled, otherwise the gas oracle or the node.
+func (c *StorageClient) fees() feeSource {
+	if c.gasPrices != nil {
+		return c.gasPrices
+	}
+	return c.feeSource
+}
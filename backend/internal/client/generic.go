@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// ErrInvalidArgument is returned by Call and Transact when the arguments
+// do not match the method's inputs in the contract ABI.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Call invokes the read-only contract method by name and returns its
+// decoded outputs.  It lets callers reach methods that have no typed
+// wrapper yet.
+func (c *StorageClient) Call(ctx context.Context, method string, args ...interface{}) (_ []interface{}, err error) {
+	defer c.observe(method, time.Now(), nil, &err)
+
+	if err := c.checkArgs(method, args); err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	err = c.retry(ctx, func() error {
+		out = nil
+		return c.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling %s: %w", method, err)
+	}
+	return out, nil
+}
+
+// Transact sends a transaction invoking the contract method by name and
+// waits for it like Set does.
+func (c *StorageClient) Transact(ctx context.Context, method string, args ...interface{}) (*TxResult, error) {
+	if err := c.checkArgs(method, args); err != nil {
+		return nil, err
+	}
+	return c.transact(ctx, method, args, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.Transact(auth, method, args...)
+	})
+}
+
+// checkArgs checks that method exists and that args match its inputs in
+// number and Go type, so a mistake is reported by argument name rather
+// than by the ABI packer.  Tuples are left to the packer, which matches
+// struct fields by name.
+func (c *StorageClient) checkArgs(method string, args []interface{}) error {
+	m, ok := c.abi.Methods[method]
+	if !ok {
+		return fmt.Errorf("method %q not found in contract abi", method)
+	}
+	if len(args) != len(m.Inputs) {
+		return fmt.Errorf("%w: %s takes %d arguments, got %d", ErrInvalidArgument, method, len(m.Inputs), len(args))
+	}
+	for i, in := range m.Inputs {
+		name := in.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if args[i] == nil {
+			return fmt.Errorf("%w: %s argument %s is nil, want %s", ErrInvalidArgument, method, name, in.Type)
+		}
+		if hasTuple(&in.Type) {
+			continue
+		}
+		if want, got := in.Type.GetType(), reflect.TypeOf(args[i]); !got.AssignableTo(want) {
+			return fmt.Errorf("%w: %s argument %s has Go type %s, want %s for abi type %s", ErrInvalidArgument, method, name, got, want, in.Type)
+		}
+	}
+	return nil
+}
+
+// hasTuple reports whether t is or contains a tuple.
+func hasTuple(t *abi.Type) bool {
+	for ; t != nil; t = t.Elem {
+		if t.T == abi.TupleTy {
+			return true
+		}
+	}
+	return false
+}
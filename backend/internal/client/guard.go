@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"golang.org/x/time/rate"
+)
+
+// defaultCallTimeout bounds each request to the node when no timeout is
+// configured.
+const defaultCallTimeout = 30 * time.Second
+
+// ErrTimeout is returned when a single request to the node takes longer
+// than its timeout, as opposed to the caller's own context expiring.
+var ErrTimeout = errors.New("rpc request timed out")
+
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a context under which each request the client
+// makes to the node is bounded by d instead of the configured CallTimeout.
+// A negative d disables the per-request timeout.
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, d)
+}
+
+// guardedBackend wraps every request to a backend with an optional rate
+// limit and a per-request timeout.  Waiting for the rate limiter counts
+// towards the timeout, and both respect the caller's context.
+type guardedBackend struct {
+	ContractBackend
+	limiter *rate.Limiter // nil for no limit
+	timeout time.Duration // zero or negative for no timeout
+}
+
+// NewRateLimitedBackend wraps backend so it sends at most perSecond
+// requests per second on average, with bursts of up to burst requests.  A
+// burst below one is raised to one.
+func NewRateLimitedBackend(backend ContractBackend, perSecond float64, burst int) ContractBackend {
+	return &guardedBackend{
+		ContractBackend: backend,
+		limiter:         rate.NewLimiter(rate.Limit(perSecond), max(burst, 1)),
+	}
+}
+
+// guard applies cfg.CallTimeout and cfg.RateLimit to backend.
+func guard(cfg Config, backend ContractBackend) ContractBackend {
+	b := &guardedBackend{ContractBackend: backend, timeout: cfg.CallTimeout}
+	if cfg.RateLimit > 0 {
+		burst := cfg.RateBurst
+		if burst == 0 {
+			burst = int(math.Ceil(cfg.RateLimit))
+		}
+		b.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(burst, 1))
+	}
+	return b
+}
+
+// begin derives the context for one request and waits for the rate
+// limiter.  The returned cancel func must always be called.
+func (b *guardedBackend) begin(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	timeout := b.timeout
+	if d, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	if b.limiter != nil {
+		if err := b.limiter.Wait(ctx); err != nil {
+			return ctx, cancel, err
+		}
+	}
+	return ctx, cancel, nil
+}
+
+// end marks err as ErrTimeout if the request, rather than the caller,
+// ran out of time.
+func (b *guardedBackend) end(parent, ctx context.Context, method string, err error) error {
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s: %w", ErrTimeout, method, err)
+	}
+	return err
+}
+
+func (b *guardedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "CodeAt", err)
+	}
+	v, err := b.ContractBackend.CodeAt(rctx, contract, blockNumber)
+	return v, b.end(ctx, rctx, "CodeAt", err)
+}
+
+func (b *guardedBackend) CallContract(ctx context.Context, call jumbochain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "CallContract", err)
+	}
+	v, err := b.ContractBackend.CallContract(rctx, call, blockNumber)
+	return v, b.end(ctx, rctx, "CallContract", err)
+}
+
+func (b *guardedBackend) PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "PendingCallContract", err)
+	}
+	v, err := b.ContractBackend.PendingCallContract(rctx, call)
+	return v, b.end(ctx, rctx, "PendingCallContract", err)
+}
+
+func (b *guardedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "HeaderByNumber", err)
+	}
+	v, err := b.ContractBackend.HeaderByNumber(rctx, number)
+	return v, b.end(ctx, rctx, "HeaderByNumber", err)
+}
+
+func (b *guardedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "PendingCodeAt", err)
+	}
+	v, err := b.ContractBackend.PendingCodeAt(rctx, account)
+	return v, b.end(ctx, rctx, "PendingCodeAt", err)
+}
+
+func (b *guardedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return 0, b.end(ctx, rctx, "PendingNonceAt", err)
+	}
+	v, err := b.ContractBackend.PendingNonceAt(rctx, account)
+	return v, b.end(ctx, rctx, "PendingNonceAt", err)
+}
+
+func (b *guardedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "SuggestGasPrice", err)
+	}
+	v, err := b.ContractBackend.SuggestGasPrice(rctx)
+	return v, b.end(ctx, rctx, "SuggestGasPrice", err)
+}
+
+func (b *guardedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "SuggestGasTipCap", err)
+	}
+	v, err := b.ContractBackend.SuggestGasTipCap(rctx)
+	return v, b.end(ctx, rctx, "SuggestGasTipCap", err)
+}
+
+func (b *guardedBackend) EstimateGas(ctx context.Context, call jumbochain.CallMsg) (uint64, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return 0, b.end(ctx, rctx, "EstimateGas", err)
+	}
+	v, err := b.ContractBackend.EstimateGas(rctx, call)
+	return v, b.end(ctx, rctx, "EstimateGas", err)
+}
+
+func (b *guardedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err == nil {
+		err = b.ContractBackend.SendTransaction(rctx, tx)
+	}
+	return b.end(ctx, rctx, "SendTransaction", err)
+}
+
+func (b *guardedBackend) FilterLogs(ctx context.Context, query jumbochain.FilterQuery) ([]types.Log, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "FilterLogs", err)
+	}
+	v, err := b.ContractBackend.FilterLogs(rctx, query)
+	return v, b.end(ctx, rctx, "FilterLogs", err)
+}
+
+func (b *guardedBackend) SubscribeFilterLogs(ctx context.Context, query jumbochain.FilterQuery, ch chan<- types.Log) (jumbochain.Subscription, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "SubscribeFilterLogs", err)
+	}
+	v, err := b.ContractBackend.SubscribeFilterLogs(rctx, query, ch)
+	return v, b.end(ctx, rctx, "SubscribeFilterLogs", err)
+}
+
+func (b *guardedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "TransactionReceipt", err)
+	}
+	v, err := b.ContractBackend.TransactionReceipt(rctx, txHash)
+	return v, b.end(ctx, rctx, "TransactionReceipt", err)
+}
+
+func (b *guardedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "ChainID", err)
+	}
+	v, err := b.ContractBackend.ChainID(rctx)
+	return v, b.end(ctx, rctx, "ChainID", err)
+}
+
+func (b *guardedBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return 0, b.end(ctx, rctx, "BlockNumber", err)
+	}
+	v, err := b.ContractBackend.BlockNumber(rctx)
+	return v, b.end(ctx, rctx, "BlockNumber", err)
+}
+
+func (b *guardedBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, false, b.end(ctx, rctx, "TransactionByHash", err)
+	}
+	tx, pending, err := b.ContractBackend.TransactionByHash(rctx, hash)
+	return tx, pending, b.end(ctx, rctx, "TransactionByHash", err)
+}
+
+func (b *guardedBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "BalanceAt", err)
+	}
+	v, err := b.ContractBackend.BalanceAt(rctx, account, blockNumber)
+	return v, b.end(ctx, rctx, "BalanceAt", err)
+}
+
+func (b *guardedBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	rctx, cancel, err := b.begin(ctx)
+	defer cancel()
+	if err != nil {
+		return nil, b.end(ctx, rctx, "StorageAt", err)
+	}
+	v, err := b.ContractBackend.StorageAt(rctx, account, key, blockNumber)
+	return v, b.end(ctx, rctx, "StorageAt", err)
+}
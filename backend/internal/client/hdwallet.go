@@ -0,0 +1,133 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/accounts"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// defaultDerivationPath is the BIP-44 path of the first Ethereum account,
+// as used by most wallets.
+const defaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// errInvalidChild is returned in the astronomically unlikely case that a
+// BIP-32 derivation step produces an invalid key.  The standard says to
+// move on to the next index; we report it instead.
+var errInvalidChild = errors.New("derived key is invalid, try the next account index")
+
+// deriveKey derives the signing key at path, with index added to its last
+// component, from a BIP-39 mnemonic and optional passphrase.
+func deriveKey(mnemonic, passphrase, path string, index uint32) (*ecdsa.PrivateKey, error) {
+	dpath, err := derivationPath(path, index)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	return deriveBIP32(seed, dpath)
+}
+
+// findDerivedKey derives the first maxAccountSearch accounts of mnemonic
+// at path and returns the key of the one at address.
+func findDerivedKey(mnemonic, passphrase, path string, address common.Address) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	for index := uint32(0); index < maxAccountSearch; index++ {
+		dpath, err := derivationPath(path, index)
+		if err != nil {
+			return nil, err
+		}
+		key, err := deriveBIP32(seed, dpath)
+		if errors.Is(err, errInvalidChild) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if crypto.PubkeyToAddress(key.PublicKey) == address {
+			return key, nil
+		}
+		zeroKey(key)
+	}
+	return nil, fmt.Errorf("%w: %s is not among the first %d accounts of the mnemonic", ErrAccountNotFound, address.Hex(), maxAccountSearch)
+}
+
+// derivationPath parses path, or defaultDerivationPath if it is empty,
+// and adds index to its last component.
+func derivationPath(path string, index uint32) (accounts.DerivationPath, error) {
+	if path == "" {
+		path = defaultDerivationPath
+	}
+	dpath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing derivation path: %w", err)
+	}
+	if index != 0 {
+		last := dpath[len(dpath)-1]
+		next := uint64(last&^0x80000000) + uint64(index)
+		if next >= 0x80000000 {
+			return nil, fmt.Errorf("account index %d out of range for path %s", index, path)
+		}
+		dpath[len(dpath)-1] = last&0x80000000 | uint32(next)
+	}
+	return dpath, nil
+}
+
+// deriveBIP32 walks path from the BIP-32 master key of seed and returns the
+// private key it ends at.
+func deriveBIP32(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	n := crypto.S256().Params().N
+	k := new(big.Int).SetBytes(key)
+	if k.Sign() == 0 || k.Cmp(n) >= 0 {
+		return nil, errInvalidChild
+	}
+
+	for _, child := range path {
+		var data []byte
+		if child >= 0x80000000 {
+			data = append([]byte{0}, key...)
+		} else {
+			priv, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&priv.PublicKey)
+			zeroKey(priv)
+		}
+		data = binary.BigEndian.AppendUint32(data, child)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, errInvalidChild
+		}
+		k.Add(k, il).Mod(k, n)
+		if k.Sign() == 0 {
+			return nil, errInvalidChild
+		}
+		key = k.FillBytes(make([]byte, 32))
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(key)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/jumboclient"
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+// ParseHeaders parses RPC_HEADERS: "Name: value" pairs separated by
+// newlines or commas, such as "Authorization: Bearer xyz".  Values cannot
+// contain commas; a header that needs one has to go on its own line.
+func ParseHeaders(s string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("header %q is not of the form \"Name: value\"", redactHeaderLine(line))
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !validHeaderName(name) {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("invalid value for header %s", name)
+		}
+		headers.Add(name, value)
+	}
+	return headers, nil
+}
+
+// validHeaderName reports whether name is an HTTP token (RFC 9110).
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// redactHeaderLine masks everything after an unparsable header's name,
+// in case its value is a credential.
+func redactHeaderLine(line string) string {
+	if name, _, ok := strings.Cut(line, " "); ok {
+		return name + " " + redacted
+	}
+	return redact(line)
+}
+
+// dialRPC opens an RPC connection to url that sends cfg.RPCHeaders with
+// every request.  Over websocket they are sent with the handshake, and
+// over IPC not at all.
+func dialRPC(ctx context.Context, cfg Config, url string) (*rpc.Client, error) {
+	return rpc.DialOptions(ctx, url, rpc.WithHeaders(cfg.RPCHeaders))
+}
+
+// dialClient is dialRPC wrapped in a jumboclient.Client.
+func dialClient(ctx context.Context, cfg Config, url string) (*jumboclient.Client, error) {
+	client, err := dialRPC(ctx, cfg, url)
+	if err != nil {
+		return nil, err
+	}
+	return jumboclient.NewClient(client), nil
+}
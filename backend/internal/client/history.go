@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/history"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// record writes the outcome of a submitted transaction to cfg.TxStore, if
+// one is configured.  Failing to record is logged but does not fail the
+// transaction, which has already been sent.
+func (c *StorageClient) record(ctx context.Context, method string, args []interface{}, tx *types.Transaction, result *TxResult, err error) {
+	if c.cfg.TxStore == nil {
+		return
+	}
+	rec := history.Record{
+		Time:           time.Now().UTC(),
+		Method:         method,
+		TxHash:         tx.Hash().Hex(),
+		Status:         history.StatusError,
+		IdempotencyKey: idempotencyKeyFrom(ctx),
+	}
+	for _, arg := range args {
+		rec.Args = append(rec.Args, fmt.Sprint(arg))
+	}
+	if result != nil {
+		rec.TxHash = result.TxHash.Hex()
+		rec.BlockNumber = result.BlockNumber
+		rec.GasUsed = result.GasUsed
+		switch {
+		case result.Pending:
+			rec.Status = history.StatusPending
+		case result.Succeeded():
+			rec.Status = history.StatusSuccess
+		default:
+			rec.Status = history.StatusReverted
+		}
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	c.appendRecord(ctx, rec)
+}
+
+// submitted records tx as pending and returns the result cfg.NoWait
+// callers get in place of a receipt.
+func (c *StorageClient) submitted(ctx context.Context, method string, args []interface{}, tx *types.Transaction) *TxResult {
+	if c.cfg.TxStore != nil {
+		rec := history.Record{
+			Time:           time.Now().UTC(),
+			Method:         method,
+			TxHash:         tx.Hash().Hex(),
+			Status:         history.StatusPending,
+			IdempotencyKey: idempotencyKeyFrom(ctx),
+		}
+		for _, arg := range args {
+			rec.Args = append(rec.Args, fmt.Sprint(arg))
+		}
+		c.appendRecord(ctx, rec)
+	}
+	return &TxResult{TxHash: tx.Hash(), Pending: true}
+}
+
+// appendRecord writes rec to cfg.TxStore, logging any failure.
+func (c *StorageClient) appendRecord(ctx context.Context, rec history.Record) {
+	if err := c.cfg.TxStore.Append(rec); err != nil {
+		c.cfg.Logger.WarnContext(ctx, "recording transaction failed", "tx", rec.TxHash, "err", err)
+	}
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/history"
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// defaultIdempotencyTTL is how long a completed transaction is remembered
+// under its idempotency key when no TTL is configured.
+const defaultIdempotencyTTL = 10 * time.Minute
+
+// ErrIdempotencyConflict is returned when an idempotency key is reused for
+// a different method or different arguments.
+var ErrIdempotencyConflict = errors.New("idempotency key reused for a different request")
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context under which Set, Add, Sub and
+// Transact submit at most one transaction per key.  A repeated call with
+// the same key, while the first is pending or within cfg.IdempotencyTTL
+// of it completing, returns the first call's result instead of sending
+// again.  Calls that sent nothing, for instance because estimating gas
+// failed, are not remembered.
+//
+// Keys are tracked in memory, and also looked up in cfg.TxStore when it
+// supports queries, so they survive a restart once the transaction has
+// been recorded.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// idempotentCall is one transaction submitted under a key.  done is
+// closed once the owning call has finished; the other fields are only
+// read after that.
+type idempotentCall struct {
+	request string // method and arguments, to detect reuse of the key
+	done    chan struct{}
+
+	tx       *types.Transaction // nil if nothing was sent
+	result   *TxResult
+	err      error
+	finished time.Time
+}
+
+// idempotencyCache tracks the calls made under each key.
+type idempotencyCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*idempotentCall
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{ttl: ttl, calls: make(map[string]*idempotentCall)}
+}
+
+// claim returns the call registered under key, creating it if there is
+// none.  owner reports whether the caller created it and must finish it.
+func (ic *idempotencyCache) claim(key, request string) (call *idempotentCall, owner bool, err error) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	now := time.Now()
+	for k, call := range ic.calls {
+		if !call.finished.IsZero() && now.Sub(call.finished) > ic.ttl {
+			delete(ic.calls, k)
+		}
+	}
+	if call, ok := ic.calls[key]; ok {
+		if call.request != request {
+			return nil, false, fmt.Errorf("%w: key %q was used for %s", ErrIdempotencyConflict, key, call.request)
+		}
+		return call, false, nil
+	}
+	call = &idempotentCall{request: request, done: make(chan struct{})}
+	ic.calls[key] = call
+	return call, true, nil
+}
+
+// finish records the outcome of call and wakes its waiters.  A call that
+// sent nothing is forgotten so the key can be retried.
+func (ic *idempotencyCache) finish(key string, call *idempotentCall, tx *types.Transaction, result *TxResult, err error) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	call.tx, call.result, call.err = tx, result, err
+	call.finished = time.Now()
+	if tx == nil && result == nil {
+		delete(ic.calls, key)
+	}
+	close(call.done)
+}
+
+// describeRequest renders method and args the way history records them.
+func describeRequest(method string, args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+	return method + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// transactOnce is transact under an idempotency key.
+func (c *StorageClient) transactOnce(ctx context.Context, key, method string, args []interface{}, send func(*bind.TransactOpts) (*types.Transaction, error)) (*TxResult, error) {
+	request := describeRequest(method, args)
+	for {
+		call, owner, err := c.idempotency.claim(key, request)
+		if err != nil {
+			return nil, err
+		}
+		if owner {
+			tx, result, err := c.recorded(ctx, key, request)
+			if err == nil && result == nil {
+				tx, result, err = c.submitAndWait(ctx, method, args, send)
+			}
+			c.idempotency.finish(key, call, tx, result, err)
+			return result, err
+		}
+
+		c.cfg.Logger.DebugContext(ctx, "waiting for transaction with the same idempotency key", "key", key)
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		switch {
+		case call.result != nil && !errors.Is(call.err, ErrMiningTimeout):
+			return call.result, call.err
+		case call.tx != nil:
+			// The first caller stopped waiting, or ran out of time,
+			// but its transaction may still be mined.
+			return c.wait(ctx, method, call.tx)
+		}
+		// The first call sent nothing; try again in its place.
+	}
+}
+
+// recorded looks key up in cfg.TxStore and returns the result of the
+// transaction recorded under it within cfg.IdempotencyTTL, if any.
+func (c *StorageClient) recorded(ctx context.Context, key, request string) (*types.Transaction, *TxResult, error) {
+	finder, ok := c.cfg.TxStore.(history.Finder)
+	if !ok {
+		return nil, nil, nil
+	}
+	recs, err := finder.Find(history.Filter{IdempotencyKey: key, Since: time.Now().Add(-c.cfg.IdempotencyTTL)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("looking up idempotency key %q: %w", key, err)
+	}
+	if len(recs) == 0 {
+		return nil, nil, nil
+	}
+	rec := recs[len(recs)-1]
+	if got := describeRequest(rec.Method, stringArgs(rec.Args)); got != request {
+		return nil, nil, fmt.Errorf("%w: key %q was used for %s", ErrIdempotencyConflict, key, got)
+	}
+
+	hash := common.HexToHash(rec.TxHash)
+	receipt, err := c.Receipt(ctx, hash)
+	if errors.Is(err, jumbochain.NotFound) && rec.Status == history.StatusPending {
+		return nil, &TxResult{TxHash: hash, Pending: true}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("transaction %s sent earlier under idempotency key %q: %w", rec.TxHash, key, err)
+	}
+	result := newTxResult(receipt)
+	result.Events = c.DecodeReceiptLogs(receipt)
+	if !result.Succeeded() {
+		return nil, result, fmt.Errorf("transaction %s failed: %w", rec.TxHash, &RevertError{})
+	}
+	return nil, result, nil
+}
+
+func stringArgs(args []string) []interface{} {
+	out := make([]interface{}, len(args))
+	for i, arg := range args {
+		out[i] = arg
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// IsInitialized reports whether a value has deliberately been stored in
+// the contract.  See GetInitialized.
+func (c *StorageClient) IsInitialized(ctx context.Context) (bool, error) {
+	_, initialized, err := c.GetInitialized(ctx)
+	return initialized, err
+}
+
+// GetInitialized returns the stored value and whether it was deliberately
+// stored, so a fresh contract's 0 can be told apart from a written 0.
+//
+// The contract has no flag for this, so it is inferred: a non-zero value
+// was always stored on purpose, and a zero one only if set, add or sub
+// has ever been called, which GetInitialized finds by scanning for
+// ValueChanged events back to cfg.DeployBlock.  A contract deployed with
+// an initial value of 0 and never written reports false.  The scan can
+// take many log queries on a long chain; setting cfg.DeployBlock bounds
+// it.
+func (c *StorageClient) GetInitialized(ctx context.Context) (*big.Int, bool, error) {
+	// Read the value and scan the logs at the same block so a write
+	// landing in between cannot make the two disagree.
+	latest, err := c.resolveToBlock(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	value, err := c.GetAtBlock(ctx, latest)
+	if err != nil {
+		return nil, false, err
+	}
+	if value.Sign() != 0 {
+		return value, true, nil
+	}
+	written, err := c.hasWrites(ctx, c.cfg.DeployBlock, latest)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, written, nil
+}
+
+// hasWrites reports whether any ValueChanged event was emitted between
+// fromBlock and toBlock inclusive.  It scans newest first, since a
+// contract in use has usually been written recently, and stops at the
+// first event.
+func (c *StorageClient) hasWrites(ctx context.Context, fromBlock, toBlock uint64) (bool, error) {
+	if fromBlock > toBlock {
+		return false, nil
+	}
+	for stop := toBlock; ; {
+		start := fromBlock
+		if stop-fromBlock >= c.cfg.LogChunkSize {
+			start = stop - c.cfg.LogChunkSize + 1
+		}
+		var found bool
+		err := c.retry(ctx, func() error {
+			events, err := c.filterValueChangedRange(ctx, start, stop, nil)
+			found = len(events) > 0
+			return err
+		})
+		if err != nil {
+			return false, fmt.Errorf("filtering blocks %d-%d: %w", start, stop, err)
+		}
+		if found || start == fromBlock {
+			return found, nil
+		}
+		stop = start - 1
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestGetInitialized(t *testing.T) {
+	ctx := context.Background()
+	m := newMockBackend(t)
+	c, _ := newMockClient(t, m, Config{LogChunkSize: 1})
+
+	check := func(c *StorageClient, wantValue int64, want bool) {
+		t.Helper()
+		value, written, err := c.GetInitialized(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value.Cmp(big.NewInt(wantValue)) != 0 || written != want {
+			t.Errorf("GetInitialized = %s, %t, want %d, %t", value, written, wantValue, want)
+		}
+		if initialized, err := c.IsInitialized(ctx); err != nil || initialized != want {
+			t.Errorf("IsInitialized = %t, %v, want %t", initialized, err, want)
+		}
+	}
+
+	// A fresh contract's 0, with no ValueChanged logs.
+	check(c, 0, false)
+
+	// A deliberately stored 0 emitted a log.
+	result, err := c.Set(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(c, 0, true)
+
+	// The scan goes back through later blocks, one chunk at a time, to
+	// the write, but not before DeployBlock.
+	m.mu.Lock()
+	m.block += 2
+	m.mu.Unlock()
+	check(c, 0, true)
+	late, _ := newMockClient(t, m, Config{DeployBlock: result.BlockNumber + 1})
+	check(late, 0, false)
+
+	// A non-zero value was stored on purpose, logs or not.
+	m.setValue(big.NewInt(5))
+	check(late, 5, true)
+}
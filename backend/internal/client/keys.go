@@ -0,0 +1,144 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/accounts/keystore"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+// ErrAccountNotFound is returned when the account selected by
+// AccountIndex or SignerAddress is not among the configured keys.
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrNoSigningKey is returned when the configuration names no key to sign
+// with, and ErrInvalidKey when PRIVATE_KEY is not a hex-encoded secp256k1
+// key.
+var (
+	ErrNoSigningKey = errors.New("no signing key")
+	ErrInvalidKey   = errors.New("invalid private key")
+)
+
+// maxAccountSearch is how many accounts of a mnemonic are derived looking
+// for SignerAddress.
+const maxAccountSearch = 100
+
+// loadSigningKey returns the key transactions are signed with.  An
+// encrypted keystore takes precedence over a mnemonic, and a mnemonic over
+// a raw hex key.  If cfg.SignerAddress is set the key must belong to it.
+func loadSigningKey(cfg Config) (*ecdsa.PrivateKey, error) {
+	key, err := selectSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SignerAddress != "" {
+		want := common.HexToAddress(cfg.SignerAddress)
+		if got := crypto.PubkeyToAddress(key.PublicKey); got != want {
+			zeroKey(key)
+			return nil, fmt.Errorf("%w: signing key is for %s, not SIGNER_ADDRESS %s", ErrAccountNotFound, got.Hex(), want.Hex())
+		}
+	}
+	return key, nil
+}
+
+func selectSigningKey(cfg Config) (*ecdsa.PrivateKey, error) {
+	if cfg.KeystorePath != "" {
+		path, err := keystoreFile(cfg.KeystorePath, cfg.SignerAddress, cfg.AccountIndex)
+		if err != nil {
+			return nil, err
+		}
+		return loadKeystore(path, cfg.KeystorePassword)
+	}
+	if cfg.Mnemonic != "" {
+		if cfg.SignerAddress != "" {
+			return findDerivedKey(cfg.Mnemonic, cfg.MnemonicPassphrase, cfg.DerivationPath, common.HexToAddress(cfg.SignerAddress))
+		}
+		return deriveKey(cfg.Mnemonic, cfg.MnemonicPassphrase, cfg.DerivationPath, cfg.AccountIndex)
+	}
+	if cfg.PrivateKey != "" {
+		key, err := crypto.HexToECDSA(cfg.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: set PRIVATE_KEY, MNEMONIC, or KEYSTORE_PATH and KEYSTORE_PASSWORD", ErrNoSigningKey)
+}
+
+// keystoreFile returns path if it is a keystore file.  If it is a
+// directory of them, as geth keeps, it returns the file for address if
+// one is given, otherwise the index-th in name order, which for files
+// named by geth is the order they were created in.
+func keystoreFile(path, address string, index uint32) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("reading keystore: %w", err)
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", fmt.Errorf("reading keystore: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+
+	if address == "" {
+		if int(index) >= len(files) {
+			return "", fmt.Errorf("%w: account index %d, keystore %s holds %d", ErrAccountNotFound, index, path, len(files))
+		}
+		return files[index], nil
+	}
+	want := common.HexToAddress(address)
+	for _, file := range files {
+		// Keystore files name their address in the clear, so only the
+		// selected one needs decrypting.
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("reading keystore: %w", err)
+		}
+		var header struct {
+			Address string `json:"address"`
+		}
+		if json.Unmarshal(data, &header) == nil && common.IsHexAddress(header.Address) && common.HexToAddress(header.Address) == want {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no key for %s in keystore %s", ErrAccountNotFound, want.Hex(), path)
+}
+
+// loadKeystore decrypts a geth style JSON keystore file.
+func loadKeystore(path, password string) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keystore: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting keystore %s: %w", path, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// zeroKey overwrites the secret scalar of k in place.
+func zeroKey(k *ecdsa.PrivateKey) {
+	if k == nil || k.D == nil {
+		return
+	}
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Source names the configuration layer a setting was taken from.
+type Source string
+
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceNetwork Source = "network"
+	SourceFile    Source = "file"
+	SourceDefault Source = "default" // not set at any layer
+)
+
+// secretSettings are masked in the Settings returned by Layers.Load.
+var secretSettings = map[string]bool{
+	"PRIVATE_KEY":         true,
+	"KEYSTORE_PASSWORD":   true,
+	"MNEMONIC":            true,
+	"MNEMONIC_PASSPHRASE": true,
+	"RPC_HEADERS":         true,
+}
+
+// Setting is one resolved setting, named by its environment variable.
+// Secret values are masked.
+type Setting struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// Layers describes where settings come from.  Every setting is named by
+// its environment variable and can be given at any layer; the first layer
+// that sets it wins, in the order
+//
+//	Flags > environment > Network in NetworksPath > FilePath > defaults
+type Layers struct {
+	// Flags holds settings given on the command line.
+	Flags map[string]string
+
+	// NetworksPath and Network select an entry of a networks file, which
+	// can set RPC_URL, WS_URL, CHAIN_ID and CONTRACT_ADDRESS.
+	NetworksPath string
+	Network      string
+
+	// FilePath, if set, is a JSON config file holding an object of
+	// settings, such as {"RPC_URL": "http://localhost:8545", "GAS_BUFFER": "20%"}.
+	FilePath string
+}
+
+// SettingFlags collects the settings given on a command line as repeated
+// NAME=value flags, for Layers.Flags.  It implements flag.Value.
+type SettingFlags map[string]string
+
+func (s SettingFlags) String() string { return "" }
+
+func (s SettingFlags) Set(arg string) error {
+	name, value, ok := strings.Cut(arg, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("want NAME=value, got %q", arg)
+	}
+	s[name] = value
+	return nil
+}
+
+// Load resolves the layers into a Config.  It also returns every setting
+// the Config reads, in the order read, with the layer it came from.
+func (l Layers) Load() (Config, []Setting, error) {
+	var file map[string]string
+	if l.FilePath != "" {
+		var err error
+		if file, err = LoadConfigFile(l.FilePath); err != nil {
+			return Config{}, nil, err
+		}
+	}
+	network, err := l.networkSettings()
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	var settings []Setting
+	seen := make(map[string]bool)
+	lookup := func(name string) string {
+		value, source := "", SourceDefault
+		if v, ok := l.Flags[name]; ok {
+			value, source = v, SourceFlag
+		} else if v := os.Getenv(name); v != "" {
+			value, source = v, SourceEnv
+		} else if v, ok := network[name]; ok {
+			value, source = v, SourceNetwork
+		} else if v, ok := file[name]; ok {
+			value, source = v, SourceFile
+		}
+		if !seen[name] {
+			seen[name] = true
+			shown := value
+			if secretSettings[name] {
+				shown = redact(value)
+			}
+			settings = append(settings, Setting{Name: name, Value: shown, Source: source})
+		}
+		return value
+	}
+
+	cfg, err := configFromLookup(lookup)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, settings, nil
+}
+
+// networkSettings returns the settings of the selected network entry.
+func (l Layers) networkSettings() (map[string]string, error) {
+	if l.Network == "" {
+		return nil, nil
+	}
+	networks, err := LoadNetworks(l.NetworksPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading networks: %w", err)
+	}
+	n, ok := networks[l.Network]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q (configured: %v)", l.Network, networks.Names())
+	}
+
+	settings := make(map[string]string)
+	for name, v := range map[string]string{
+		"RPC_URL":          n.RPCURL,
+		"WS_URL":           n.WSURL,
+		"CONTRACT_ADDRESS": n.ContractAddress,
+	} {
+		if v != "" {
+			settings[name] = v
+		}
+	}
+	if n.ChainID != 0 {
+		settings["CHAIN_ID"] = strconv.FormatUint(n.ChainID, 10)
+	}
+	return settings, nil
+}
+
+// LoadConfigFile reads a JSON config file of settings named by their
+// environment variables.  Values may be strings, numbers or booleans.
+func LoadConfigFile(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // keep large integers such as gas prices exact
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	settings := make(map[string]string, len(values))
+	for name, v := range values {
+		switch v := v.(type) {
+		case string:
+			settings[name] = v
+		case json.Number:
+			settings[name] = v.String()
+		case bool:
+			settings[name] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("parsing %s: %s must be a string, number or boolean", path, name)
+		}
+	}
+	return settings, nil
+}
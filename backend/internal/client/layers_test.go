@@ -0,0 +1,52 @@
+package client
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayersPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"RPC_URL": "http://file:8545", "WS_URL": "ws://file:8546", "GAS_BUFFER": 30}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RPC_URL", "http://env:8545")
+	t.Setenv("WS_URL", "ws://env:8546")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	settings := make(SettingFlags)
+	fs.Var(settings, "set", "")
+	if err := fs.Parse([]string{"-set", "RPC_URL=http://flag:8545"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-set", "RPC_URL"}); err == nil {
+		t.Error("-set without a value was accepted")
+	}
+
+	cfg, resolved, err := Layers{Flags: settings, FilePath: file}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := make(map[string]Source)
+	for _, s := range resolved {
+		sources[s.Name] = s.Source
+	}
+	for _, tt := range []struct {
+		name, got, want string
+		source          Source
+	}{
+		{"RPC_URL", cfg.RPCURL, "http://flag:8545", SourceFlag},
+		{"WS_URL", cfg.WSURL, "ws://env:8546", SourceEnv},
+	} {
+		if tt.got != tt.want || sources[tt.name] != tt.source {
+			t.Errorf("%s = %q from %s, want %q from %s", tt.name, tt.got, sources[tt.name], tt.want, tt.source)
+		}
+	}
+	if sources["GAS_BUFFER"] != SourceFile {
+		t.Errorf("GAS_BUFFER from %s, want %s", sources["GAS_BUFFER"], SourceFile)
+	}
+}
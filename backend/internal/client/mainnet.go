@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"math/big"
+)
+
+// mainnetChainIDs are production chains, where transactions cost real
+// money: Ethereum, Optimism, BNB Smart Chain, Gnosis, Polygon, Fantom,
+// zkSync Era, Base, Arbitrum One, Avalanche C-Chain and Linea.
+var mainnetChainIDs = map[uint64]bool{
+	1:     true,
+	10:    true,
+	56:    true,
+	100:   true,
+	137:   true,
+	250:   true,
+	324:   true,
+	8453:  true,
+	42161: true,
+	43114: true,
+	59144: true,
+}
+
+// IsMainnet reports whether chainID is a known production chain.
+func IsMainnet(chainID *big.Int) bool {
+	return chainID.IsUint64() && mainnetChainIDs[chainID.Uint64()]
+}
+
+// ChainID returns the ID of the chain the client sends transactions on.
+func (c *StorageClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID(ctx)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrCrossChain is returned by Migrate when the two contracts are on
+// different chains and that was not explicitly allowed.
+var ErrCrossChain = errors.New("contracts are on different chains")
+
+// ErrMigrationMismatch is returned by Migrate when the new contract does
+// not hold the migrated value once the transaction is mined.
+var ErrMigrationMismatch = errors.New("migrated value does not match")
+
+// Migration reports what Migrate copied.
+type Migration struct {
+	Value  *big.Int  // value read from the old contract
+	Result *TxResult // the Set on the new contract
+}
+
+// Migrate copies the value of the contract src is bound to into the one
+// dst is bound to, with a Set sent from dst's account, and reads it back
+// from dst to confirm the copy.  Another writer to either contract in
+// between makes the check fail with ErrMigrationMismatch, returned along
+// with the Migration.
+//
+// The contracts may be on different chains only if allowCrossChain is
+// set, since a value means something different on another network;
+// otherwise Migrate fails with ErrCrossChain before sending anything.
+// dst must wait for its transactions: cfg.NoWait is refused.
+func Migrate(ctx context.Context, src, dst *StorageClient, allowCrossChain bool) (*Migration, error) {
+	if dst.cfg.NoWait {
+		return nil, fmt.Errorf("%w: migration needs to wait for its transaction, NoWait is set", ErrInvalidArgument)
+	}
+	srcChain, err := src.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dstChain, err := dst.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sameChain := srcChain.Cmp(dstChain) == 0
+	if sameChain && src.Address() == dst.Address() {
+		return nil, fmt.Errorf("%w: old and new contract are both %s", ErrInvalidArgument, src.Address().Hex())
+	}
+	if !sameChain && !allowCrossChain {
+		return nil, fmt.Errorf("%w: %s is on chain %s, %s on chain %s", ErrCrossChain,
+			src.Address().Hex(), srcChain, dst.Address().Hex(), dstChain)
+	}
+
+	value, err := src.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading old contract %s: %w", src.Address().Hex(), err)
+	}
+	result, err := dst.Set(ctx, value)
+	if err != nil {
+		return nil, fmt.Errorf("writing new contract %s: %w", dst.Address().Hex(), err)
+	}
+	if result.DryRun {
+		return &Migration{Value: value, Result: result}, nil
+	}
+
+	got, err := dst.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("verifying new contract %s: %w", dst.Address().Hex(), err)
+	}
+	if got.Cmp(value) != 0 {
+		return &Migration{Value: value, Result: result}, fmt.Errorf("%w: %s holds %s, expected %s", ErrMigrationMismatch, dst.Address().Hex(), got, value)
+	}
+	dst.cfg.Logger.InfoContext(ctx, "migrated value", "from", src.Address(), "to", dst.Address(), "value", value)
+	return &Migration{Value: value, Result: result}, nil
+}
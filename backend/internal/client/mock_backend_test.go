@@ -0,0 +1,442 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+// mockContract is the address the mockBackend serves SimpleStorage at.
+var mockContract = common.HexToAddress("0x5fbdb2315678afecb367f032d93f642f64180aa3")
+
+// mockBackend is an in-memory ContractBackend for unit tests.  It plays a
+// SimpleStorage contract at mockContract: calls to get return value, and
+// set, add and sub transactions update it and are mined as soon as their
+// nonce is next, each in a block of its own.  A sub that would underflow
+// reverts, as the real contract does.
+//
+// Every method can be made to fail with failWith, and the transactions
+// sent are kept for the test to inspect with sentTxs.
+type mockBackend struct {
+	abi *abi.ABI
+
+	mu       sync.Mutex
+	chainID  *big.Int
+	baseFee  *big.Int
+	balance  *big.Int
+	value    *big.Int
+	block    uint64
+	nonces   map[common.Address]uint64
+	queued   map[common.Address]map[uint64]*types.Transaction
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	logs     []types.Log
+	errs     map[string]error
+}
+
+func newMockBackend(t testing.TB) *mockBackend {
+	t.Helper()
+	parsed, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mockBackend{
+		abi:      parsed,
+		chainID:  big.NewInt(1337),
+		baseFee:  big.NewInt(1e9),
+		balance:  new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)),
+		value:    new(big.Int),
+		block:    1,
+		nonces:   make(map[common.Address]uint64),
+		queued:   make(map[common.Address]map[uint64]*types.Transaction),
+		receipts: make(map[common.Hash]*types.Receipt),
+		errs:     make(map[string]error),
+	}
+}
+
+// newMockClient returns a client signing with a fresh key over m, bound to
+// mockContract.  cfg may adjust any other setting.
+func newMockClient(t testing.TB, m *mockBackend, cfg Config) (*StorageClient, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.PrivateKey = common.Bytes2Hex(crypto.FromECDSA(key))
+	cfg.ContractAddress = mockContract.Hex()
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Millisecond
+	}
+	c, err := NewStorageClientWithBackend(context.Background(), cfg, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c, key
+}
+
+// failWith makes every later call of method, named as on ContractBackend,
+// fail with err.  A nil err restores the method.
+func (m *mockBackend) failWith(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// setValue stores v in the contract.
+func (m *mockBackend) setValue(v *big.Int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.value = new(big.Int).Set(v)
+}
+
+// stored returns the value held by the contract.
+func (m *mockBackend) stored() *big.Int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return new(big.Int).Set(m.value)
+}
+
+// sentTxs returns the transactions accepted by SendTransaction, in order.
+func (m *mockBackend) sentTxs() []*types.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*types.Transaction(nil), m.sent...)
+}
+
+// fail returns the error programmed for method.  m.mu must be held.
+func (m *mockBackend) fail(method string) error {
+	return m.errs[method]
+}
+
+// mockRevert is a revert as a node reports it, with the revert payload
+// attached as error data.
+type mockRevert struct{ data []byte }
+
+func (e *mockRevert) Error() string          { return "execution reverted" }
+func (e *mockRevert) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// exec runs calldata against the contract as from would, returning the
+// output, the value stored afterwards and the event emitted, if any.
+// m.mu must be held.
+func (m *mockBackend) exec(from common.Address, data []byte) ([]byte, *big.Int, *types.Log, error) {
+	if len(data) < 4 {
+		return nil, nil, nil, &mockRevert{}
+	}
+	method, err := m.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, nil, &mockRevert{}
+	}
+	if method.Name == "get" {
+		out, err := method.Outputs.Pack(m.value)
+		return out, m.value, nil, err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil || len(args) != 1 {
+		return nil, nil, nil, &mockRevert{}
+	}
+	x := args[0].(*big.Int)
+	next := new(big.Int)
+	switch method.Name {
+	case "set":
+		next.Set(x)
+	case "add":
+		next.Add(m.value, x)
+		if next.BitLen() > 256 {
+			return nil, nil, nil, &mockRevert{data: append(common.CopyBytes(panicSelector), common.LeftPadBytes([]byte{0x11}, 32)...)}
+		}
+	case "sub":
+		if x.Cmp(m.value) > 0 {
+			reason, _ := abi.Arguments{{Type: mustType("string")}}.Pack(underflowReason)
+			return nil, nil, nil, &mockRevert{data: append([]byte{0x08, 0xc3, 0x79, 0xa0}, reason...)}
+		}
+		next.Sub(m.value, x)
+	default:
+		return nil, nil, nil, &mockRevert{}
+	}
+	event := m.abi.Events["ValueChanged"]
+	logData, err := event.Inputs.NonIndexed().Pack(m.value, next)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	log := &types.Log{
+		Address: mockContract,
+		Topics:  []common.Hash{event.ID, common.BytesToHash(from.Bytes())},
+		Data:    logData,
+	}
+	var out []byte
+	if len(method.Outputs) > 0 {
+		out, _ = method.Outputs.Pack(next)
+	}
+	return out, next, log, nil
+}
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+func (m *mockBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("CodeAt"); err != nil {
+		return nil, err
+	}
+	if account != mockContract {
+		return nil, nil
+	}
+	return []byte{0x60, 0x80, 0x60, 0x40, 0x52}, nil
+}
+
+func (m *mockBackend) CallContract(ctx context.Context, call jumbochain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("CallContract"); err != nil {
+		return nil, err
+	}
+	return m.call(call)
+}
+
+func (m *mockBackend) PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("PendingCallContract"); err != nil {
+		return nil, err
+	}
+	return m.call(call)
+}
+
+// call runs call without changing the contract.  m.mu must be held.
+func (m *mockBackend) call(call jumbochain.CallMsg) ([]byte, error) {
+	if call.To == nil || *call.To != mockContract {
+		return nil, nil
+	}
+	out, _, _, err := m.exec(call.From, call.Data)
+	return out, err
+}
+
+func (m *mockBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("HeaderByNumber"); err != nil {
+		return nil, err
+	}
+	head := &types.Header{Number: new(big.Int).SetUint64(m.block), GasLimit: 30000000}
+	if m.baseFee != nil {
+		head.BaseFee = new(big.Int).Set(m.baseFee)
+	}
+	return head, nil
+}
+
+func (m *mockBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return m.CodeAt(ctx, account, nil)
+}
+
+func (m *mockBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("PendingNonceAt"); err != nil {
+		return 0, err
+	}
+	return m.nonces[account], nil
+}
+
+func (m *mockBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("SuggestGasPrice"); err != nil {
+		return nil, err
+	}
+	return big.NewInt(2e9), nil
+}
+
+func (m *mockBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("SuggestGasTipCap"); err != nil {
+		return nil, err
+	}
+	return big.NewInt(1e9), nil
+}
+
+func (m *mockBackend) EstimateGas(ctx context.Context, call jumbochain.CallMsg) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("EstimateGas"); err != nil {
+		return 0, err
+	}
+	if _, err := m.call(call); err != nil {
+		return 0, err
+	}
+	return 30000, nil
+}
+
+// SendTransaction checks tx is signed for the mock's chain, then mines
+// it.  Like a node's transaction pool, it holds a transaction whose nonce
+// is ahead of the sender's until the ones before it arrive.
+func (m *mockBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("SendTransaction"); err != nil {
+		return err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(m.chainID), tx)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	if tx.Nonce() < m.nonces[from] {
+		return errors.New("nonce too low")
+	}
+	if m.queued[from] == nil {
+		m.queued[from] = make(map[uint64]*types.Transaction)
+	}
+	if _, ok := m.queued[from][tx.Nonce()]; ok {
+		return errors.New("replacement transaction underpriced")
+	}
+	m.queued[from][tx.Nonce()] = tx
+	m.sent = append(m.sent, tx)
+	for {
+		next, ok := m.queued[from][m.nonces[from]]
+		if !ok {
+			return nil
+		}
+		delete(m.queued[from], m.nonces[from])
+		m.nonces[from]++
+		m.mine(from, next)
+	}
+}
+
+// mine includes tx in a new block.  m.mu must be held.
+func (m *mockBackend) mine(from common.Address, tx *types.Transaction) {
+	m.block++
+	receipt := &types.Receipt{
+		Type:              tx.Type(),
+		Status:            types.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		BlockNumber:       new(big.Int).SetUint64(m.block),
+		GasUsed:           30000,
+		CumulativeGasUsed: 30000,
+		EffectiveGasPrice: tx.GasPrice(),
+	}
+	if to := tx.To(); to != nil && *to == mockContract {
+		if _, next, log, err := m.exec(from, tx.Data()); err != nil {
+			receipt.Status = types.ReceiptStatusFailed
+		} else if log != nil {
+			m.value = next
+			log.TxHash = tx.Hash()
+			log.BlockNumber = m.block
+			receipt.Logs = []*types.Log{log}
+			m.logs = append(m.logs, *log)
+		}
+	}
+	m.receipts[tx.Hash()] = receipt
+}
+
+func (m *mockBackend) FilterLogs(ctx context.Context, query jumbochain.FilterQuery) ([]types.Log, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("FilterLogs"); err != nil {
+		return nil, err
+	}
+	var logs []types.Log
+	for _, log := range m.logs {
+		if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() ||
+			query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (m *mockBackend) SubscribeFilterLogs(ctx context.Context, query jumbochain.FilterQuery, ch chan<- types.Log) (jumbochain.Subscription, error) {
+	return nil, errors.New("mock backend does not support subscriptions")
+}
+
+func (m *mockBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("TransactionReceipt"); err != nil {
+		return nil, err
+	}
+	receipt, ok := m.receipts[txHash]
+	if !ok {
+		return nil, jumbochain.NotFound
+	}
+	return receipt, nil
+}
+
+func (m *mockBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("ChainID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(m.chainID), nil
+}
+
+func (m *mockBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("BalanceAt"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(m.balance), nil
+}
+
+func (m *mockBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("StorageAt"); err != nil {
+		return nil, err
+	}
+	if account != mockContract || key != (common.Hash{}) {
+		return make([]byte, 32), nil
+	}
+	return common.LeftPadBytes(m.value.Bytes(), 32), nil
+}
+
+func (m *mockBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("BlockNumber"); err != nil {
+		return 0, err
+	}
+	return m.block, nil
+}
+
+func (m *mockBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail("TransactionByHash"); err != nil {
+		return nil, false, err
+	}
+	for _, tx := range m.sent {
+		if tx.Hash() == hash {
+			return tx, false, nil
+		}
+	}
+	return nil, false, jumbochain.NotFound
+}
+
+func (m *mockBackend) Close() {}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// multicallABI declares the one Multicall3 method GetAll uses.
+const multicallABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var parsedMulticallABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// multicall3Call and multicall3Result mirror Multicall3's Call3 and Result
+// structs.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// GetAll returns the stored value of every registered contract, keyed by
+// contract address.  Contracts on an endpoint with a MulticallAddress are
+// read in a single aggregate3 call; the rest are read one at a time.
+func (r *Registry) GetAll(ctx context.Context) (map[common.Address]*big.Int, error) {
+	// Group clients by endpoint and multicall contract, in name order so
+	// errors are deterministic.
+	type group struct {
+		rpcURL, multicall string
+	}
+	groups := make(map[group][]*StorageClient)
+	var order []group
+	for _, name := range r.Names() {
+		c := r.clients[name]
+		g := group{c.cfg.RPCURL, c.cfg.MulticallAddress}
+		if _, ok := groups[g]; !ok {
+			order = append(order, g)
+		}
+		groups[g] = append(groups[g], c)
+	}
+
+	values := make(map[common.Address]*big.Int, len(r.clients))
+	for _, g := range order {
+		clients := groups[g]
+		if g.multicall == "" {
+			for _, c := range clients {
+				value, err := c.Get(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("reading %s: %w", c.address.Hex(), err)
+				}
+				values[c.address] = value
+			}
+			continue
+		}
+		if err := getMulticall(ctx, common.HexToAddress(g.multicall), clients, values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// getMulticall reads the value of each of clients, which share an
+// endpoint, through the Multicall3 contract at multicall and stores them
+// in values.
+func getMulticall(ctx context.Context, multicall common.Address, clients []*StorageClient, values map[common.Address]*big.Int) error {
+	first := clients[0]
+	calls := make([]multicall3Call, len(clients))
+	for i, c := range clients {
+		data, err := c.abi.Pack("get")
+		if err != nil {
+			return err
+		}
+		calls[i] = multicall3Call{Target: c.address, AllowFailure: true, CallData: data}
+	}
+	input, err := parsedMulticallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	err = first.retry(ctx, func() (err error) {
+		out, err = first.client.CallContract(ctx, jumbochain.CallMsg{To: &multicall, Data: input}, nil)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("calling multicall %s: %w", multicall.Hex(), classifyNodeError(err))
+	}
+	if len(out) == 0 {
+		return fmt.Errorf("multicall %s: %w", multicall.Hex(), ErrContractNotDeployed)
+	}
+	unpacked, err := parsedMulticallABI.Unpack("aggregate3", out)
+	if err != nil {
+		return fmt.Errorf("decoding multicall result: %w", err)
+	}
+	results := *abi.ConvertType(unpacked[0], new([]multicall3Result)).(*[]multicall3Result)
+	if len(results) != len(clients) {
+		return fmt.Errorf("multicall %s returned %d results for %d calls", multicall.Hex(), len(results), len(clients))
+	}
+
+	var failed []string
+	for i, c := range clients {
+		res := results[i]
+		if !res.Success {
+			failed = append(failed, c.address.Hex())
+			continue
+		}
+		decoded, err := c.abi.Unpack("get", res.ReturnData)
+		if err != nil || len(decoded) != 1 {
+			failed = append(failed, c.address.Hex())
+			continue
+		}
+		values[c.address] = decoded[0].(*big.Int)
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed) // report in address order
+		return fmt.Errorf("reading %s through multicall failed", strings.Join(failed, ", "))
+	}
+	return nil
+}
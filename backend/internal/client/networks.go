@@ -0,0 +1,64 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Network describes one deployment of the contract.
+type Network struct {
+	RPCURL          string `json:"rpcUrl"`
+	WSURL           string `json:"wsUrl,omitempty"`
+	ChainID         uint64 `json:"chainId,omitempty"` // overrides the chain ID reported by the node
+	ContractAddress string `json:"contractAddress"`
+}
+
+// Networks maps network names to their settings.  It is stored on disk as
+//
+//	{
+//	  "networks": {
+//	    "local":   {"rpcUrl": "http://localhost:8545", "wsUrl": "ws://localhost:8546", "contractAddress": "0x..."},
+//	    "testnet": {"rpcUrl": "https://...", "chainId": 11155111, "contractAddress": "0x..."}
+//	  }
+//	}
+type Networks map[string]Network
+
+// LoadNetworks reads a networks file from path.
+func LoadNetworks(path string) (Networks, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Networks Networks `json:"networks"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return file.Networks, nil
+}
+
+// Names returns the configured network names in sorted order.
+func (n Networks) Names() []string {
+	names := make([]string, 0, len(n))
+	for name := range n {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadConfig builds a Config for the named network in networksPath, with
+// any settings present in the environment taking precedence, and those in
+// the JSON config file named by CONFIG_FILE, if any, filling in the rest.
+// An empty network name skips the networks file.  See Layers.
+func LoadConfig(networksPath, network string) (Config, error) {
+	cfg, _, err := Layers{
+		NetworksPath: networksPath,
+		Network:      network,
+		FilePath:     os.Getenv("CONFIG_FILE"),
+	}.Load()
+	return cfg, err
+}
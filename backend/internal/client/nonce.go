@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// NonceSource reports the next nonce the chain expects from an account.
+// *jumboclient.Client satisfies it.
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out nonces per sender without waiting for earlier
+// transactions to be mined.  The first nonce for an address is read from
+// the chain; later ones are tracked locally until a failure forces a resync.
+type NonceManager struct {
+	source NonceSource
+
+	mu     sync.Mutex
+	nonces map[common.Address]uint64 // next nonce to hand out
+}
+
+// NewNonceManager returns a NonceManager that syncs from source.
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source: source,
+		nonces: make(map[common.Address]uint64),
+	}
+}
+
+// Next reserves and returns the next nonce for account.
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, ok := m.nonces[account]
+	if !ok {
+		pending, err := m.source.PendingNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+		nonce = pending
+	}
+	m.nonces[account] = nonce + 1
+	return nonce, nil
+}
+
+// Rollback returns nonce to the pool after a transaction using it could not
+// be submitted.  If later nonces have already been handed out the local
+// state can no longer be trusted, so the account is resynced from the chain
+// on its next use instead.
+func (m *NonceManager) Rollback(account common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if next, ok := m.nonces[account]; ok && next == nonce+1 {
+		m.nonces[account] = nonce
+		return
+	}
+	delete(m.nonces, account)
+}
+
+// Reset forgets the tracked nonce for account so the next call to Next
+// reads it from the chain again.
+func (m *NonceManager) Reset(account common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.nonces, account)
+}
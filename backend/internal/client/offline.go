@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// OfflineSigner builds and signs contract transactions without a node
+// connection, so the signing key can stay on an air-gapped machine.  The
+// resulting raw transactions are submitted elsewhere with Broadcast.
+type OfflineSigner struct {
+	address    common.Address
+	abi        *abi.ABI
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewOfflineSigner loads the signing key and contract address from cfg.
+// Nothing in cfg that concerns the node connection is used.
+func NewOfflineSigner(cfg Config) (*OfflineSigner, error) {
+	address, err := ParseAddress(cfg.ContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("contract address: %w", err)
+	}
+	parsed, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("parsing contract abi: %w", err)
+	}
+	privateKey, err := loadSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &OfflineSigner{address: address, abi: parsed, privateKey: privateKey}, nil
+}
+
+// Close wipes the signing key from memory.  The signer must not be used
+// afterwards.
+func (s *OfflineSigner) Close() {
+	zeroKey(s.privateKey)
+}
+
+// BuildSignedTx signs a legacy transaction calling method with args on the
+// contract.  Since no node is consulted, the caller supplies everything the
+// client would otherwise look up: the chain ID, the sender's next nonce,
+// the gas price and the gas limit.  It returns the transaction along with
+// its hex-encoded RLP form, ready for Broadcast.
+func (s *OfflineSigner) BuildSignedTx(chainID *big.Int, nonce uint64, gasPrice *big.Int, gasLimit uint64, method string, args ...interface{}) (*types.Transaction, string, error) {
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, "", fmt.Errorf("chain id must be positive")
+	}
+	if gasPrice == nil {
+		return nil, "", fmt.Errorf("gas price not set")
+	}
+	if gasLimit == 0 {
+		return nil, "", fmt.Errorf("gas limit not set")
+	}
+	data, err := s.abi.Pack(method, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("packing %s call: %w", method, err)
+	}
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gasLimit,
+		To:       &s.address,
+		Value:    big.NewInt(0),
+		Data:     data,
+	}), types.LatestSignerForChainID(chainID), s.privateKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("signing %s transaction: %w", method, err)
+	}
+	if err := checkReplayProtected(tx, chainID); err != nil {
+		return nil, "", err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, "", fmt.Errorf("encoding transaction: %w", err)
+	}
+	return tx, hexutil.Encode(raw), nil
+}
+
+// Broadcast submits a transaction signed elsewhere, given in the hex
+// encoding BuildSignedTx produces, and waits for it like Set does.
+func (c *StorageClient) Broadcast(ctx context.Context, rawTx string) (result *TxResult, err error) {
+	defer c.observe("broadcast", time.Now(), &result, &err)
+
+	raw, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("decoding raw transaction: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decoding raw transaction: %w", err)
+	}
+	if c.cfg.ExpectedChainID != nil {
+		chainID, err := c.chainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if tx.ChainId().Cmp(chainID) != 0 {
+			return nil, fmt.Errorf("%w: transaction is signed for chain %s, node is on chain %s", ErrChainMismatch, tx.ChainId(), chainID)
+		}
+	}
+	if err := c.retry(ctx, func() error { return c.client.SendTransaction(ctx, tx) }); err != nil {
+		return nil, fmt.Errorf("sending transaction %s: %w", tx.Hash().Hex(), classifyNodeError(err))
+	}
+	c.cfg.Logger.DebugContext(ctx, "transaction broadcast", "tx", tx.Hash())
+	result, err = c.wait(ctx, "broadcast", tx)
+	c.record(ctx, "broadcast", nil, tx, result, err)
+	return result, err
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// ErrNoOwner is returned by Owner when the contract has no owner() view.
+// The stock SimpleStorage has none; Ownable variants of it do.
+var ErrNoOwner = errors.New("contract has no owner")
+
+// ErrNotAuthorized is returned when a transaction reverted because the
+// sender lacks permission, as reported by OpenZeppelin's Ownable and
+// AccessControl checks.
+var ErrNotAuthorized = errors.New("sender is not authorized")
+
+// ownerSelector is the selector of owner().
+var ownerSelector = []byte{0x8d, 0xa5, 0xcb, 0x5b}
+
+// unauthorizedSelector is the selector of the
+// OwnableUnauthorizedAccount(address) error raised by OpenZeppelin 5.
+var unauthorizedSelector = []byte{0x11, 0x8c, 0xda, 0xa7}
+
+// Owner returns the owner of the contract, for contracts that expose an
+// owner() view, or ErrNoOwner.
+func (c *StorageClient) Owner(ctx context.Context) (common.Address, error) {
+	var out []byte
+	err := c.retry(ctx, func() (err error) {
+		out, err = c.client.CallContract(ctx, jumbochain.CallMsg{To: &c.address, Data: ownerSelector}, nil)
+		return err
+	})
+	if err != nil {
+		if errors.Is(classifyNodeError(err), ErrReverted) {
+			return common.Address{}, ErrNoOwner
+		}
+		return common.Address{}, fmt.Errorf("calling owner: %w", err)
+	}
+	if len(out) != 32 {
+		return common.Address{}, ErrNoOwner
+	}
+	return common.BytesToAddress(out), nil
+}
+
+// warnIfNotOwner logs a warning when the contract has an owner and it is
+// not the sender, since owner-only methods will then revert.  A contract
+// found to have no owner is not asked again.
+func (c *StorageClient) warnIfNotOwner(ctx context.Context) {
+	if c.ownerless.Load() {
+		return
+	}
+	owner, err := c.Owner(ctx)
+	switch {
+	case errors.Is(err, ErrNoOwner):
+		c.ownerless.Store(true)
+	case err != nil:
+		c.cfg.Logger.DebugContext(ctx, "could not read contract owner", "err", err)
+	case owner != c.from:
+		c.cfg.Logger.WarnContext(ctx, "sender is not the contract owner, the transaction may be rejected",
+			"sender", c.from, "owner", owner)
+	}
+}
+
+// decodeUnauthorized decodes an OwnableUnauthorizedAccount error payload.
+func decodeUnauthorized(data []byte) (string, bool) {
+	if len(data) != 4+32 || !bytes.Equal(data[:4], unauthorizedSelector) {
+		return "", false
+	}
+	return fmt.Sprintf("OwnableUnauthorizedAccount(%s)", common.BytesToAddress(data[4:]).Hex()), true
+}
+
+// isNotAuthorized reports whether reason is one of the permission
+// failures of OpenZeppelin's Ownable and AccessControl, in their 4.x
+// string and 5.x custom error forms.
+func isNotAuthorized(reason string) bool {
+	return strings.Contains(reason, "Ownable: caller is not the owner") ||
+		strings.HasPrefix(reason, "OwnableUnauthorizedAccount(") ||
+		strings.HasPrefix(reason, "AccessControl: account ") && strings.Contains(reason, "is missing role")
+}
+
+// authorizationError marks err with ErrNotAuthorized if the failure it
+// reports is a permission check.
+func authorizationError(result *TxResult, err error) error {
+	if err != nil && isNotAuthorized(failureReason(result, err)) {
+		return fmt.Errorf("%w: %w", ErrNotAuthorized, err)
+	}
+	return err
+}
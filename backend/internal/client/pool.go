@@ -0,0 +1,303 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"sync"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+var errPoolClosed = errors.New("connection pool closed")
+
+// connState is implemented by backends that track whether their
+// connection is up: supervised connections and pools of them.
+type connState interface {
+	State() ConnState
+}
+
+// dialPooled is like dialSupervised, but with cfg.RPCPoolSize above one
+// it returns a pool that opens up to that many connections to url.  The
+// returned state is nil if nothing tracks the connection, as for a single
+// http connection.
+func dialPooled(ctx context.Context, cfg Config, rawURL string) (ContractBackend, connState, error) {
+	if cfg.RPCPoolSize <= 1 {
+		backend, conn, err := dialSupervised(ctx, cfg, rawURL)
+		if err != nil || conn == nil {
+			return backend, nil, err
+		}
+		return backend, conn, nil
+	}
+	p := &poolBackend{
+		url:     rawURL,
+		label:   endpointLabel(rawURL),
+		size:    cfg.RPCPoolSize,
+		logger:  cfg.Logger,
+		metrics: cfg.Metrics,
+		dial: func(ctx context.Context) (ContractBackend, *supervisedBackend, error) {
+			return dialSupervised(ctx, cfg, rawURL)
+		},
+	}
+	// Dial the first connection now so a bad url fails here.
+	if _, err := p.add(ctx); err != nil {
+		return nil, nil, err
+	}
+	return p, p, nil
+}
+
+// poolBackend spreads requests over up to size connections to the same
+// endpoint.  Each request goes to the connection with the fewest requests
+// in flight; a new connection is dialed, up to size, only when every
+// existing one is busy.  If dialing fails the request shares a busy
+// connection instead.
+//
+// Over http the transport already reuses and multiplexes connections, so
+// a pool mostly helps websocket and IPC endpoints, where a single
+// connection serializes every request.
+type poolBackend struct {
+	url     string
+	label   string // url without credentials, for metrics
+	size    int
+	logger  *slog.Logger
+	metrics *metrics.Metrics
+	dial    func(ctx context.Context) (ContractBackend, *supervisedBackend, error)
+
+	mu      sync.Mutex
+	members []*poolMember
+	dialing bool
+	closed  bool
+}
+
+type poolMember struct {
+	backend  ContractBackend
+	conn     *supervisedBackend // nil for http
+	inFlight int
+}
+
+// acquire returns the least busy connection, dialing a new one first if
+// all are busy and the pool has room.  release must be called once the
+// request is done.
+func (p *poolBackend) acquire(ctx context.Context) *poolMember {
+	p.mu.Lock()
+	m := p.leastBusy()
+	grow := m.inFlight > 0 && len(p.members) < p.size && !p.dialing && !p.closed
+	if grow {
+		p.dialing = true
+	}
+	p.mu.Unlock()
+
+	if grow {
+		if added, err := p.add(ctx); err != nil {
+			p.logger.WarnContext(ctx, "opening pooled connection failed", "url", redactURL(p.url), "err", err)
+		} else {
+			m = added
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if grow {
+		p.dialing = false
+	}
+	m.inFlight++
+	p.observe()
+	return m
+}
+
+func (p *poolBackend) release(m *poolMember) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m.inFlight--
+	p.observe()
+}
+
+// leastBusy returns the member with the fewest requests in flight.  The
+// caller holds p.mu.
+func (p *poolBackend) leastBusy() *poolMember {
+	best := p.members[0]
+	for _, m := range p.members[1:] {
+		if m.inFlight < best.inFlight {
+			best = m
+		}
+	}
+	return best
+}
+
+// add dials a connection and adds it to the pool.
+func (p *poolBackend) add(ctx context.Context) (*poolMember, error) {
+	backend, conn, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := &poolMember{backend: backend, conn: conn}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		backend.Close()
+		return nil, errPoolClosed
+	}
+	p.members = append(p.members, m)
+	p.observe()
+	return m, nil
+}
+
+// observe records the pool's size and load.  The caller holds p.mu.
+func (p *poolBackend) observe() {
+	inUse := 0
+	for _, m := range p.members {
+		if m.inFlight > 0 {
+			inUse++
+		}
+	}
+	p.metrics.ObservePool(p.label, len(p.members), inUse)
+}
+
+// endpointLabel identifies the endpoint at rawURL by host, leaving out
+// the path and query where providers put API keys.  IPC endpoints have no
+// host and are all labelled "ipc".
+func endpointLabel(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "ipc"
+	}
+	return u.Host
+}
+
+// State reports ConnReconnecting while any pooled connection is being
+// re-dialed.
+func (p *poolBackend) State() ConnState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, m := range p.members {
+		if m.conn != nil && m.conn.State() == ConnReconnecting {
+			return ConnReconnecting
+		}
+	}
+	return ConnConnected
+}
+
+// Close closes every pooled connection.
+func (p *poolBackend) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, m := range p.members {
+		m.backend.Close()
+	}
+}
+
+// SubscribeFilterLogs holds its connection only while subscribing; the
+// subscription then stays on it without counting as a request in flight.
+func (p *poolBackend) SubscribeFilterLogs(ctx context.Context, query jumbochain.FilterQuery, ch chan<- types.Log) (jumbochain.Subscription, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.SubscribeFilterLogs(ctx, query, ch)
+}
+
+func (p *poolBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+func (p *poolBackend) CallContract(ctx context.Context, call jumbochain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.CallContract(ctx, call, blockNumber)
+}
+
+func (p *poolBackend) PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.PendingCallContract(ctx, call)
+}
+
+func (p *poolBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.HeaderByNumber(ctx, number)
+}
+
+func (p *poolBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.PendingCodeAt(ctx, account)
+}
+
+func (p *poolBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.PendingNonceAt(ctx, account)
+}
+
+func (p *poolBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.SuggestGasPrice(ctx)
+}
+
+func (p *poolBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.SuggestGasTipCap(ctx)
+}
+
+func (p *poolBackend) EstimateGas(ctx context.Context, call jumbochain.CallMsg) (uint64, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.EstimateGas(ctx, call)
+}
+
+func (p *poolBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.SendTransaction(ctx, tx)
+}
+
+func (p *poolBackend) FilterLogs(ctx context.Context, query jumbochain.FilterQuery) ([]types.Log, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.FilterLogs(ctx, query)
+}
+
+func (p *poolBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.TransactionReceipt(ctx, txHash)
+}
+
+func (p *poolBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.ChainID(ctx)
+}
+
+func (p *poolBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.BlockNumber(ctx)
+}
+
+func (p *poolBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.TransactionByHash(ctx, hash)
+}
+
+func (p *poolBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.BalanceAt(ctx, account, blockNumber)
+}
+
+func (p *poolBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	m := p.acquire(ctx)
+	defer p.release(m)
+	return m.backend.StorageAt(ctx, account, key, blockNumber)
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+const (
+	defaultReceiptCacheSize = 256
+	defaultReceiptCacheTTL  = 5 * time.Minute
+)
+
+// receiptCache is a size-bounded LRU of mined receipts whose entries also
+// expire after a TTL, so a receipt invalidated by a reorg is not served
+// forever.  Only mined receipts are ever stored; a pending transaction
+// always goes back to the node.
+type receiptCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[common.Hash]*list.Element
+}
+
+type receiptEntry struct {
+	hash    common.Hash
+	receipt *types.Receipt
+	expires time.Time
+}
+
+// newReceiptCache returns a cache of at most size receipts, or nil if size
+// is not positive.  A nil cache stores nothing.
+func newReceiptCache(size int, ttl time.Duration) *receiptCache {
+	if size <= 0 {
+		return nil
+	}
+	return &receiptCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[common.Hash]*list.Element),
+	}
+}
+
+func (rc *receiptCache) get(hash common.Hash) (*types.Receipt, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	el, ok := rc.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*receiptEntry)
+	if time.Now().After(entry.expires) {
+		rc.order.Remove(el)
+		delete(rc.entries, hash)
+		return nil, false
+	}
+	rc.order.MoveToFront(el)
+	return entry.receipt, true
+}
+
+func (rc *receiptCache) put(receipt *types.Receipt) {
+	if rc == nil || receipt == nil || receipt.BlockNumber == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	expires := time.Now().Add(rc.ttl)
+	if el, ok := rc.entries[receipt.TxHash]; ok {
+		el.Value = &receiptEntry{hash: receipt.TxHash, receipt: receipt, expires: expires}
+		rc.order.MoveToFront(el)
+		return
+	}
+	rc.entries[receipt.TxHash] = rc.order.PushFront(&receiptEntry{hash: receipt.TxHash, receipt: receipt, expires: expires})
+	for rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*receiptEntry).hash)
+	}
+}
+
+// Receipt returns the receipt of the mined transaction hash, from the
+// cache if it was seen recently.  It returns jumbochain.NotFound for a
+// transaction that is pending or unknown.
+func (c *StorageClient) Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if receipt, ok := c.receipts.get(hash); ok {
+		return receipt, nil
+	}
+	var receipt *types.Receipt
+	err := c.retry(ctx, func() (err error) {
+		receipt, err = c.client.TransactionReceipt(ctx, hash)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching receipt of %s: %w", hash.Hex(), err)
+	}
+	c.receipts.put(receipt)
+	return receipt, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// ReceiptLog is a log from a transaction receipt.  Logs emitted by the
+// contract are decoded against its ABI; any other log, or one the ABI
+// does not describe, has only Raw set.
+type ReceiptLog struct {
+	Event  string                 // event name, empty if not decoded
+	Fields map[string]interface{} // event arguments by name, indexed ones included
+
+	// ValueChanged is set for ValueChanged events.
+	ValueChanged *ValueChanged
+
+	Raw *types.Log
+}
+
+// DecodeReceiptLogs decodes the logs in receipt, in order, so callers can
+// check a transaction emitted the events they expected.
+func (c *StorageClient) DecodeReceiptLogs(receipt *types.Receipt) []ReceiptLog {
+	logs := make([]ReceiptLog, len(receipt.Logs))
+	for i, log := range receipt.Logs {
+		logs[i] = c.decodeLog(log)
+	}
+	return logs
+}
+
+// decodeLog decodes log if it was emitted by the contract.  Logs that fail
+// to decode are returned raw rather than failing the whole receipt.
+func (c *StorageClient) decodeLog(log *types.Log) ReceiptLog {
+	raw := ReceiptLog{Raw: log}
+	if log.Address != c.address || len(log.Topics) == 0 {
+		return raw
+	}
+	ev, err := c.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return raw
+	}
+	fields := make(map[string]interface{})
+	if err := ev.Inputs.UnpackIntoMap(fields, log.Data); err != nil {
+		return raw
+	}
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(fields, indexed, log.Topics[1:]); err != nil {
+		return raw
+	}
+	decoded := ReceiptLog{Event: ev.Name, Fields: fields, Raw: log}
+	if ev.Name == "ValueChanged" {
+		if vc, err := c.instance.ParseValueChanged(*log); err == nil {
+			decoded.ValueChanged = newValueChanged(vc)
+		}
+	}
+	return decoded
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// redacted replaces secrets wherever a Config is printed or logged.
+const redacted = "***"
+
+// redact masks s unless it is empty, so output still shows whether a
+// secret was set.
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURL reduces rawURL to its scheme and host, dropping the user
+// info, path and query where providers put API keys.  A path with no
+// scheme, as IPC endpoints are, is kept without any query; a URL that
+// does not parse is masked entirely.
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	if u.Scheme == "" {
+		return u.Path
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// redactURLError masks the URL that net/http and url.Parse quote in their
+// errors, as redactURL does.
+func redactURLError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		ue.URL = redactURL(ue.URL)
+	}
+	return err
+}
+
+// Redacted returns a copy of cfg with the private key, keystore password,
+// mnemonic, mnemonic passphrase and RPC header values masked, and the
+// node, signer and gas oracle URLs cut down to their scheme and host.
+func (cfg Config) Redacted() Config {
+	cfg.RPCURL = redactURL(cfg.RPCURL)
+	cfg.WSURL = redactURL(cfg.WSURL)
+	cfg.SignerURL = redactURL(cfg.SignerURL)
+	cfg.GasOracleURL = redactURL(cfg.GasOracleURL)
+	cfg.PrivateKey = redact(cfg.PrivateKey)
+	cfg.KeystorePassword = redact(cfg.KeystorePassword)
+	cfg.Mnemonic = redact(cfg.Mnemonic)
+	cfg.MnemonicPassphrase = redact(cfg.MnemonicPassphrase)
+	if cfg.RPCHeaders != nil {
+		masked := make(http.Header, len(cfg.RPCHeaders))
+		for name, values := range cfg.RPCHeaders {
+			for range values {
+				masked.Add(name, redacted)
+			}
+		}
+		cfg.RPCHeaders = masked
+	}
+	return cfg
+}
+
+// String implements fmt.Stringer with secrets masked, so printing a
+// Config with %v or %+v cannot leak them.
+func (cfg Config) String() string {
+	type plain Config // drops the methods, avoiding recursion
+	return fmt.Sprintf("%+v", plain(cfg.Redacted()))
+}
+
+// GoString implements fmt.GoStringer so %#v is masked too.
+func (cfg Config) GoString() string {
+	return "client.Config" + cfg.String()
+}
+
+// LogValue implements slog.LogValuer, logging the settings of interest
+// with secrets masked.
+func (cfg Config) LogValue() slog.Value {
+	cfg = cfg.Redacted()
+	return slog.GroupValue(
+		slog.String("rpcUrl", cfg.RPCURL),
+		slog.String("wsUrl", cfg.WSURL),
+		slog.Any("rpcHeaders", cfg.RPCHeaders),
+		slog.String("contractAddress", cfg.ContractAddress),
+		slog.String("privateKey", cfg.PrivateKey),
+		slog.String("keystorePath", cfg.KeystorePath),
+		slog.String("keystorePassword", cfg.KeystorePassword),
+		slog.String("mnemonic", cfg.Mnemonic),
+		slog.String("mnemonicPassphrase", cfg.MnemonicPassphrase),
+		slog.String("derivationPath", cfg.DerivationPath),
+		slog.Any("accountIndex", cfg.AccountIndex),
+		slog.Any("chainId", cfg.ChainID),
+		slog.Any("expectedChainId", cfg.ExpectedChainID),
+		slog.String("feeMode", string(cfg.FeeMode)),
+		slog.Any("gasPrice", cfg.GasPrice),
+		slog.String("gasOracleUrl", cfg.GasOracleURL),
+		slog.String("gasTier", string(cfg.GasTier)),
+		slog.Uint64("confirmations", cfg.Confirmations),
+		slog.Duration("callTimeout", cfg.CallTimeout),
+		slog.Float64("rateLimit", cfg.RateLimit),
+		slog.Bool("dryRun", cfg.DryRun),
+	)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Registry holds several StorageClients under caller-chosen names.
+// Contracts on the same RPC endpoint share one connection, one rate limit,
+// one circuit breaker and one nonce manager, so clients signing with the
+// same key do not race for nonces.
+type Registry struct {
+	clients  map[string]*StorageClient
+	backends []ContractBackend
+}
+
+// sharedBackend is handed to each client of a Registry in place of the
+// real connection, so closing one client does not cut off the others.  The
+// Registry closes the connection itself.
+type sharedBackend struct {
+	ContractBackend
+}
+
+func (sharedBackend) Close() {}
+
+// NewRegistry connects a client for every entry of configs, keyed by the
+// same name.  On error every client already created is closed.
+func NewRegistry(ctx context.Context, configs map[string]Config) (_ *Registry, err error) {
+	r := &Registry{clients: make(map[string]*StorageClient, len(configs))}
+	defer func() {
+		if err != nil {
+			r.Close()
+		}
+	}()
+
+	type endpoint struct {
+		backend ContractBackend
+		nonces  *NonceManager
+		conn    connState       // nil for a single http connection
+		breaker *breakerBackend // nil if disabled
+	}
+	endpoints := make(map[string]endpoint)
+
+	// Visit names in order so errors and dial order are deterministic.
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cfg := configs[name]
+		if cfg.RPCURL == "" {
+			return nil, fmt.Errorf("contract %q: rpc url not set", name)
+		}
+		cfg.setDefaults()
+
+		ep, ok := endpoints[cfg.RPCURL]
+		if !ok {
+			backend, conn, err := dialPooled(ctx, cfg, cfg.RPCURL)
+			if err != nil {
+				return nil, fmt.Errorf("contract %q: %w", name, err)
+			}
+			r.backends = append(r.backends, backend)
+			guarded := guard(cfg, backend)
+			breaker := newBreaker(cfg, guarded)
+			if breaker != nil {
+				guarded = breaker
+			}
+			ep = endpoint{backend: sharedBackend{guarded}, nonces: NewNonceManager(guarded), conn: conn, breaker: breaker}
+			endpoints[cfg.RPCURL] = ep
+		}
+
+		c, err := NewStorageClientWithBackend(ctx, cfg, ep.backend)
+		if err != nil {
+			return nil, fmt.Errorf("contract %q: %w", name, err)
+		}
+		c.nonces = ep.nonces
+		if ep.conn != nil {
+			c.conns = append(c.conns, ep.conn)
+		}
+		c.breaker = ep.breaker
+		r.clients[name] = c
+	}
+	return r, nil
+}
+
+// Get returns the client registered as name, or nil if there is none.
+func (r *Registry) Get(name string) *StorageClient {
+	return r.clients[name]
+}
+
+// Names returns the registered names in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every client and the connections they share.  The registry
+// must not be used afterwards.
+func (r *Registry) Close() {
+	for _, c := range r.clients {
+		c.Close()
+	}
+	for _, b := range r.backends {
+		b.Close()
+	}
+}
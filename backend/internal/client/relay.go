@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/common/math"
+	"github.com/jumbochain/jumbochain-go/crypto"
+	"github.com/jumbochain/jumbochain-go/signer/core/apitypes"
+)
+
+// defaultRelayNonceFile is where the relayer remembers the next nonce of
+// each user.
+const defaultRelayNonceFile = ".simple-storage-relay-nonces.json"
+
+// relayDomainName and relayDomainVersion identify relay requests in their
+// EIP-712 domain, alongside the chain and the contract address.
+const (
+	relayDomainName    = "SimpleStorage"
+	relayDomainVersion = "1"
+)
+
+// relayTypes declares the message a user signs to have the relayer send
+// a transaction for them.
+var relayTypes = apitypes.Types{
+	"Relay": {
+		{Name: "from", Type: "address"},
+		{Name: "method", Type: "string"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+	},
+}
+
+// ErrRelayDisabled is returned by Relay unless cfg.Relay is set.
+var ErrRelayDisabled = errors.New("relaying is disabled")
+
+// ErrRelaySignature is returned when a relay request is not signed by the
+// user it names.
+var ErrRelaySignature = errors.New("relay request signature does not match sender")
+
+// ErrRelayNonce is returned when a relay request does not carry its
+// user's next nonce, because it was relayed already or signed out of
+// order.
+var ErrRelayNonce = errors.New("relay request nonce is not the next one")
+
+// RelayRequest is a set, add or sub that From signed for a relayer to
+// send, paying the gas.  Signature is From's EIP-712 signature over the
+// other fields, bound to the contract and its chain.
+type RelayRequest struct {
+	From      common.Address
+	Method    string
+	Value     *big.Int
+	Nonce     uint64
+	Signature []byte
+}
+
+// relayRequestJSON is the wire form of a RelayRequest.  Numbers are
+// decimal strings, as elsewhere in the API, so JavaScript clients do not
+// lose precision.
+type relayRequestJSON struct {
+	From      common.Address `json:"from"`
+	Method    string         `json:"method"`
+	Value     string         `json:"value"`
+	Nonce     string         `json:"nonce"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+func (r RelayRequest) MarshalJSON() ([]byte, error) {
+	value := ""
+	if r.Value != nil {
+		value = r.Value.String()
+	}
+	return json.Marshal(relayRequestJSON{
+		From:      r.From,
+		Method:    r.Method,
+		Value:     value,
+		Nonce:     strconv.FormatUint(r.Nonce, 10),
+		Signature: r.Signature,
+	})
+}
+
+func (r *RelayRequest) UnmarshalJSON(data []byte) error {
+	var dec relayRequestJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	value, err := ParseValue(dec.Value)
+	if err != nil {
+		return fmt.Errorf("relay request value: %w", err)
+	}
+	nonce, err := strconv.ParseUint(dec.Nonce, 10, 64)
+	if err != nil {
+		return fmt.Errorf("relay request nonce: %w", err)
+	}
+	*r = RelayRequest{
+		From:      dec.From,
+		Method:    dec.Method,
+		Value:     value,
+		Nonce:     nonce,
+		Signature: dec.Signature,
+	}
+	return nil
+}
+
+// relayMethods are the transactions a relay request may ask for.
+func (c *StorageClient) relayMethods() map[string]transactFunc {
+	return map[string]transactFunc{
+		"set": c.Set,
+		"add": c.Add,
+		"sub": c.Sub,
+	}
+}
+
+// transactFunc is the signature shared by Set, Add and Sub.
+type transactFunc func(ctx context.Context, value *big.Int) (*TxResult, error)
+
+// SignRelay signs a relay request for method with value as the client's
+// account, to be handed to a relayer.  nonce must be the account's next
+// relay nonce, which the relayer reports with RelayNonce.
+func (c *StorageClient) SignRelay(ctx context.Context, method string, value *big.Int, nonce uint64) (*RelayRequest, error) {
+	req := &RelayRequest{From: c.from, Method: method, Value: value, Nonce: nonce}
+	if _, ok := c.relayMethods()[method]; !ok {
+		return nil, fmt.Errorf("%w: cannot relay method %q", ErrInvalidArgument, method)
+	}
+	domain, message, err := c.relayMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req.Signature, err = c.SignTypedData(domain, relayTypes, message)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Relay sends the transaction req asks for, signed and paid for by the
+// client's account, once it has checked that req.From signed it and that
+// it carries their next nonce.  The contract does not check signatures
+// itself yet, so it sees the relayer as the sender.
+//
+// A nonce is used up once its transaction has been submitted, even if
+// the transaction then fails; a request that fails before submission may
+// be retried with the same nonce.
+func (c *StorageClient) Relay(ctx context.Context, req *RelayRequest) (*TxResult, error) {
+	if !c.cfg.Relay {
+		return nil, ErrRelayDisabled
+	}
+	send, ok := c.relayMethods()[req.Method]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot relay method %q", ErrInvalidArgument, req.Method)
+	}
+	if req.Value == nil {
+		return nil, fmt.Errorf("%w: relay request has no value", ErrInvalidArgument)
+	}
+	domain, message, err := c.relayMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := newTypedData(domain, relayTypes, message)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := RecoverTypedData(data, req.Signature)
+	if err != nil || signer != req.From {
+		return nil, fmt.Errorf("%w: request for %s", ErrRelaySignature, req.From.Hex())
+	}
+
+	if err := c.useRelayNonce(req.From, req.Nonce); err != nil {
+		return nil, err
+	}
+	c.cfg.Logger.InfoContext(ctx, "relaying transaction",
+		"user", req.From, "method", req.Method, "value", req.Value, "nonce", req.Nonce)
+	result, err := send(ctx, req.Value)
+	if err != nil && result == nil {
+		// Nothing was submitted, so the request may be tried again.
+		if rerr := c.releaseRelayNonce(req.From, req.Nonce); rerr != nil {
+			c.cfg.Logger.WarnContext(ctx, "releasing relay nonce failed", "user", req.From, "nonce", req.Nonce, "err", rerr)
+		}
+	}
+	return result, err
+}
+
+// RelayNonce returns the nonce user's next relay request must carry.
+func (c *StorageClient) RelayNonce(user common.Address) (uint64, error) {
+	c.relayMu.Lock()
+	defer c.relayMu.Unlock()
+	state, err := loadRelayNonces(c.cfg.RelayNonceFile)
+	if err != nil {
+		return 0, err
+	}
+	return state.Nonces[user], nil
+}
+
+// relayMessage returns the EIP-712 domain and message that req's
+// signature covers.
+func (c *StorageClient) relayMessage(ctx context.Context, req *RelayRequest) (apitypes.TypedDataDomain, apitypes.TypedDataMessage, error) {
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return apitypes.TypedDataDomain{}, nil, err
+	}
+	domain := apitypes.TypedDataDomain{
+		Name:              relayDomainName,
+		Version:           relayDomainVersion,
+		ChainId:           (*math.HexOrDecimal256)(chainID),
+		VerifyingContract: c.address.Hex(),
+	}
+	message := apitypes.TypedDataMessage{
+		"from":   req.From.Hex(),
+		"method": req.Method,
+		"value":  req.Value.String(),
+		"nonce":  strconv.FormatUint(req.Nonce, 10),
+	}
+	return domain, message, nil
+}
+
+// RecoverTypedData returns the account whose EIP-712 signature over data
+// sig is.  V may be 0 or 1 as well as 27 or 28.
+func RecoverTypedData(data apitypes.TypedData, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("signature is %d bytes, expected 65", len(sig))
+	}
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("hashing typed data: %w", err)
+	}
+	sig = common.CopyBytes(sig)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// relayNonces is the content of cfg.RelayNonceFile: the next nonce of
+// each user that has had a request relayed.
+type relayNonces struct {
+	Nonces map[common.Address]uint64 `json:"nonces"`
+}
+
+// useRelayNonce advances user's nonce past nonce, failing with
+// ErrRelayNonce unless nonce is the next one.
+func (c *StorageClient) useRelayNonce(user common.Address, nonce uint64) error {
+	c.relayMu.Lock()
+	defer c.relayMu.Unlock()
+	state, err := loadRelayNonces(c.cfg.RelayNonceFile)
+	if err != nil {
+		return err
+	}
+	if next := state.Nonces[user]; nonce != next {
+		return fmt.Errorf("%w: got %d, expected %d", ErrRelayNonce, nonce, next)
+	}
+	state.Nonces[user] = nonce + 1
+	if err := state.save(c.cfg.RelayNonceFile); err != nil {
+		return fmt.Errorf("recording relay nonce: %w", err)
+	}
+	return nil
+}
+
+// releaseRelayNonce undoes useRelayNonce, unless a later nonce of user
+// has been used since.
+func (c *StorageClient) releaseRelayNonce(user common.Address, nonce uint64) error {
+	c.relayMu.Lock()
+	defer c.relayMu.Unlock()
+	state, err := loadRelayNonces(c.cfg.RelayNonceFile)
+	if err != nil {
+		return err
+	}
+	if state.Nonces[user] != nonce+1 {
+		return nil
+	}
+	state.Nonces[user] = nonce
+	return state.save(c.cfg.RelayNonceFile)
+}
+
+// loadRelayNonces reads the nonce file at path.  A missing file means no
+// requests have been relayed.
+func loadRelayNonces(path string) (*relayNonces, error) {
+	state := &relayNonces{Nonces: make(map[common.Address]uint64)}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if state.Nonces == nil {
+		state.Nonces = make(map[common.Address]uint64)
+	}
+	return state, nil
+}
+
+// save writes s to path.
+func (s *relayNonces) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
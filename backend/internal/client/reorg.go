@@ -0,0 +1,105 @@
+package client
+
+import (
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// reorgWindow is how many blocks back the watcher remembers delivered
+// events, and so how deep a reorg it can undo by itself.
+const reorgWindow = 128
+
+// reorgTracker remembers the events a watcher has delivered from recent
+// blocks, so that when a block is replaced the events it held can be
+// reported as removed.  Nodes send removed logs themselves when they see
+// a reorg, but not for one that happened while the subscription was being
+// re-established.  A replaced block is only noticed once an event arrives
+// from the block that replaced it.
+type reorgTracker struct {
+	hashes    map[uint64]common.Hash // block number to the hash its events came from
+	delivered map[uint64][]*ValueChanged
+	head      uint64 // highest block delivered from
+}
+
+func newReorgTracker() *reorgTracker {
+	return &reorgTracker{
+		hashes:    make(map[uint64]common.Hash),
+		delivered: make(map[uint64][]*ValueChanged),
+	}
+}
+
+// observe records ev and returns what to deliver for it, in order: for an
+// event from a block replaced since, first removals of the events seen from
+// the replaced blocks, then ev.  A removed log is delivered only if its
+// event was, and not already reported removed.
+func (t *reorgTracker) observe(ev *ValueChanged) []*ValueChanged {
+	if ev.Removed {
+		if ev.BlockNumber+reorgWindow <= t.head {
+			return []*ValueChanged{ev} // too old to track
+		}
+		if !t.forget(ev) {
+			return nil
+		}
+		return []*ValueChanged{ev}
+	}
+
+	var out []*ValueChanged
+	if hash, ok := t.hashes[ev.BlockNumber]; ok && hash != ev.BlockHash {
+		// Every later block was built on the replaced one.
+		out = t.removeFrom(ev.BlockNumber)
+		t.head = ev.BlockNumber
+	}
+	t.hashes[ev.BlockNumber] = ev.BlockHash
+	t.delivered[ev.BlockNumber] = append(t.delivered[ev.BlockNumber], ev)
+	if ev.BlockNumber > t.head {
+		t.head = ev.BlockNumber
+		t.prune()
+	}
+	return append(out, ev)
+}
+
+// removeFrom forgets every event from block number onwards and returns
+// them, marked removed, newest first so consumers can undo them in order.
+func (t *reorgTracker) removeFrom(number uint64) []*ValueChanged {
+	var removed []*ValueChanged
+	for n := t.head; n >= number; n-- {
+		events := t.delivered[n]
+		for i := len(events) - 1; i >= 0; i-- {
+			gone := *events[i]
+			gone.Removed = true
+			removed = append(removed, &gone)
+		}
+		delete(t.delivered, n)
+		delete(t.hashes, n)
+		if n == 0 {
+			break
+		}
+	}
+	return removed
+}
+
+// forget drops the delivered event ev is the removal of, reporting whether
+// there was one.
+func (t *reorgTracker) forget(ev *ValueChanged) bool {
+	events := t.delivered[ev.BlockNumber]
+	for i, seen := range events {
+		if seen.BlockHash == ev.BlockHash && seen.TxHash == ev.TxHash && seen.LogIndex == ev.LogIndex {
+			t.delivered[ev.BlockNumber] = append(events[:i:i], events[i+1:]...)
+			if len(t.delivered[ev.BlockNumber]) == 0 {
+				delete(t.delivered, ev.BlockNumber)
+				delete(t.hashes, ev.BlockNumber)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// prune forgets blocks that have fallen out of the window.
+func (t *reorgTracker) prune() {
+	for n := range t.hashes {
+		if n+reorgWindow <= t.head {
+			delete(t.hashes, n)
+			delete(t.delivered, n)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// TxResult summarises a mined transaction.  In dry-run mode nothing is
+// mined: only DryRun, EstimatedGas and NewValue are set.  A Set skipped
+// because the value was unchanged sets only Skipped and NewValue.  With
+// cfg.NoWait only TxHash and Pending are set.
+type TxResult struct {
+	TxHash            common.Hash
+	BlockNumber       uint64
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	Status            uint64
+	EffectiveGasPrice *big.Int
+	Logs              []*types.Log
+	Events            []ReceiptLog // Logs, decoded by DecodeReceiptLogs
+	RevertReason      string       // set when Status reports failure and the reason is known
+
+	DryRun       bool
+	EstimatedGas uint64
+	NewValue     *big.Int // value the contract would hold afterwards
+
+	Skipped bool
+
+	Pending bool // submitted but not yet known to be mined
+}
+
+// newTxResult copies the fields of interest out of receipt.
+func newTxResult(receipt *types.Receipt) *TxResult {
+	res := &TxResult{
+		TxHash:            receipt.TxHash,
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		Status:            receipt.Status,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+		Logs:              receipt.Logs,
+	}
+	if receipt.BlockNumber != nil {
+		res.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	return res
+}
+
+// Succeeded reports whether the transaction executed without reverting.
+func (r *TxResult) Succeeded() bool {
+	return r.Status == types.ReceiptStatusSuccessful
+}
+
+// Fee returns the amount paid for the transaction in wei.
+func (r *TxResult) Fee() *big.Int {
+	if r.EffectiveGasPrice == nil {
+		return nil
+	}
+	return new(big.Int).Mul(r.EffectiveGasPrice, new(big.Int).SetUint64(r.GasUsed))
+}
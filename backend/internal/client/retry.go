@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 500 * time.Millisecond
+)
+
+// withRetry calls fn until it succeeds, returns an error that is not
+// transient, or has been called attempts times.  The delay between calls
+// starts at backoff and doubles each time, with jitter.
+func withRetry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil || !isTransient(err) || i == attempts-1 {
+			return err
+		}
+
+		delay := backoff << i
+		delay = delay/2 + rand.N(delay/2+1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// isTransient reports whether err looks like a network or node availability
+// problem worth retrying, as opposed to a revert or a bad request.
+func isTransient(err error) bool {
+	// A single request timing out is worth retrying as long as the
+	// caller's own context is still live, which withRetry checks.
+	if errors.Is(err, ErrTimeout) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// retry runs fn under the client's retry policy.
+func (c *StorageClient) retry(ctx context.Context, fn func() error) error {
+	return withRetry(ctx, c.cfg.RetryAttempts, c.cfg.RetryBackoff, fn)
+}
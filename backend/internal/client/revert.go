@@ -0,0 +1,155 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/accounts/abi"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+// ErrReverted is returned when a transaction was mined but its execution
+// reverted, or when estimating or simulating it reverted.  The error is
+// then a *RevertError carrying the reason.
+var ErrReverted = errors.New("execution reverted")
+
+// RevertError describes a reverted execution.  It matches ErrReverted
+// under errors.Is; use errors.As to read the reason.
+type RevertError struct {
+	// Reason is the decoded revert reason, or "" if none was recovered.
+	Reason string
+
+	err error // the node's error, if the revert was reported by one
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return ErrReverted.Error()
+	}
+	return ErrReverted.Error() + ": " + e.Reason
+}
+
+func (e *RevertError) Is(target error) bool { return target == ErrReverted }
+
+func (e *RevertError) Unwrap() error { return e.err }
+
+// ErrUnderflow is returned by Sub when the contract refuses to go below
+// zero.
+var ErrUnderflow = errors.New("stored value would underflow")
+
+// underflowReason is the revert reason of the contract's underflow check.
+const underflowReason = "SimpleStorage: underflow"
+
+// panicSelector is the selector of the Panic(uint256) error Solidity raises
+// on failed assertions and checked arithmetic.
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// panicReasons describes the Solidity panic codes.
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized function",
+}
+
+// revertReason re-executes the reverted transaction tx with eth_call at
+// the block it was mined in and returns the decoded revert reason.  It
+// returns "" if the reason cannot be recovered, for instance because the
+// call no longer reverts in that block's state.
+func (c *StorageClient) revertReason(ctx context.Context, tx *types.Transaction, block *big.Int) string {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return ""
+	}
+	var data []byte
+	err = c.retry(ctx, func() (err error) {
+		_, err = c.client.CallContract(ctx, jumbochain.CallMsg{
+			From:  from,
+			To:    tx.To(),
+			Gas:   tx.Gas(),
+			Value: tx.Value(),
+			Data:  tx.Data(),
+		}, block)
+		if err != nil && !isTransient(err) {
+			data = revertData(err)
+			return nil
+		}
+		return err
+	})
+	if err != nil || data == nil {
+		return ""
+	}
+	return decodeRevert(data)
+}
+
+// isUnderflow reports whether a failed transaction, or the estimate or
+// simulation that preceded it, reverted because of underflow.
+func isUnderflow(result *TxResult, err error) bool {
+	reason := failureReason(result, err)
+	return reason == underflowReason || strings.HasPrefix(reason, "panic: "+panicReasons[0x11])
+}
+
+// failureReason returns the revert reason of a failed transaction, or of
+// the estimate or simulation that preceded it, or "" if there is none.
+func failureReason(result *TxResult, err error) string {
+	if result != nil {
+		return result.RevertReason
+	}
+	var re *RevertError
+	if errors.As(err, &re) {
+		return re.Reason
+	}
+	return ""
+}
+
+// revertData extracts the revert payload the node attaches to a failed
+// eth_call, if any.
+func revertData(err error) []byte {
+	var de rpc.DataError
+	if !errors.As(err, &de) {
+		return nil
+	}
+	s, ok := de.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// decodeRevert decodes a revert payload encoded as Error(string),
+// Panic(uint256) or OwnableUnauthorizedAccount(address).
+func decodeRevert(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if reason, ok := decodeUnauthorized(data); ok {
+		return reason
+	}
+	if len(data) == 4+32 && bytes.Equal(data[:4], panicSelector) {
+		code := new(big.Int).SetBytes(data[4:])
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return fmt.Sprintf("panic: %s (0x%x)", reason, code)
+			}
+		}
+		return fmt.Sprintf("panic: code 0x%x", code)
+	}
+	return fmt.Sprintf("unrecognised revert data %s", hexutil.Encode(data))
+}
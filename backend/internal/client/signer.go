@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/accounts"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/accounts/external"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+	"github.com/jumbochain/jumbochain-go/signer/core/apitypes"
+)
+
+// ErrNotReplayProtected is returned when a transaction would be signed
+// without EIP-155 replay protection, or was signed for a different chain
+// than the client's.  Such a transaction could be replayed on other chains.
+var ErrNotReplayProtected = errors.New("transaction is not replay protected")
+
+// ErrReadOnly is returned by every method that sends a transaction when
+// the client was built with cfg.ReadOnly.
+var ErrReadOnly = errors.New("client is read-only")
+
+// ErrSignerMismatch is returned when an external signer hands back a
+// transaction signed by another account, or one that differs from the
+// transaction it was asked to sign.
+var ErrSignerMismatch = errors.New("signed transaction does not match the request")
+
+// txSigner signs transactions and EIP-712 typed data for a single
+// account.
+type txSigner interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	SignTypedData(data apitypes.TypedData) ([]byte, error)
+	Close()
+}
+
+// newSigner returns the signer configured by cfg: a USB hardware wallet
+// when cfg.Signer is "hardware", an external signer such as clef when
+// cfg.SignerURL is set, otherwise a local key.
+func newSigner(cfg Config) (txSigner, error) {
+	if cfg.ReadOnly {
+		return readOnlySigner{}, nil
+	}
+	if cfg.Signer == SignerHardware {
+		return newHardwareSigner(cfg)
+	}
+	if cfg.SignerURL != "" {
+		return newRemoteSigner(cfg.SignerURL, cfg.SignerAddress)
+	}
+	key, err := loadSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &localSigner{key: key}, nil
+}
+
+// localSigner signs with a key held in memory.
+type localSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *localSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *localSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *localSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("hashing typed data: %w", err)
+	}
+	return crypto.Sign(hash, s.key)
+}
+
+// Close wipes the key from memory.
+func (s *localSigner) Close() {
+	zeroKey(s.key)
+}
+
+// readOnlySigner stands in for a signer in read-only clients.  It has no
+// account and refuses to sign.
+type readOnlySigner struct{}
+
+func (readOnlySigner) Address() common.Address { return common.Address{} }
+
+func (readOnlySigner) SignTx(*types.Transaction, *big.Int) (*types.Transaction, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlySigner) SignTypedData(apitypes.TypedData) ([]byte, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlySigner) Close() {}
+
+// remoteSigner sends transactions to an external signer speaking clef's
+// account_signTransaction API, so the key never enters this process.
+type remoteSigner struct {
+	signer  *external.ExternalSigner
+	account accounts.Account
+}
+
+// newRemoteSigner connects to the signer at url.  Transactions are signed
+// for address, or for the first account the signer lists if address is
+// empty.
+func newRemoteSigner(url, address string) (*remoteSigner, error) {
+	signer, err := external.NewExternalSigner(url)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to signer %s: %w", redactURL(url), redactURLError(err))
+	}
+	var account accounts.Account
+	if address != "" {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid signer address %q", address)
+		}
+		account.Address = common.HexToAddress(address)
+	} else {
+		listed := signer.Accounts()
+		if len(listed) == 0 {
+			return nil, fmt.Errorf("signer %s lists no accounts, set SIGNER_ADDRESS", redactURL(url))
+		}
+		account = listed[0]
+	}
+	return &remoteSigner{signer: signer, account: account}, nil
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *remoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.signer.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("external signer: %w", err)
+	}
+	if err := checkSigned(tx, signed, s.account.Address, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// checkSigned reports ErrSignerMismatch unless signed is tx signed by from
+// for chainID.  The signer is outside this process, so nothing it returns
+// is trusted: it could sign the wrong account's transaction or alter the
+// one it was given, down to its fees and access list.
+func checkSigned(tx, signed *types.Transaction, from common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSignerMismatch, err)
+	}
+	if sender != from {
+		return fmt.Errorf("%w: signed by %s, expected %s", ErrSignerMismatch, sender, from)
+	}
+	switch {
+	case signed.Type() != tx.Type():
+		return fmt.Errorf("%w: type changed from %d to %d", ErrSignerMismatch, tx.Type(), signed.Type())
+	case signed.Nonce() != tx.Nonce():
+		return fmt.Errorf("%w: nonce %d, expected %d", ErrSignerMismatch, signed.Nonce(), tx.Nonce())
+	case !sameRecipient(signed.To(), tx.To()):
+		return fmt.Errorf("%w: recipient %v, expected %v", ErrSignerMismatch, signed.To(), tx.To())
+	case signed.Value().Cmp(tx.Value()) != 0:
+		return fmt.Errorf("%w: value %s, expected %s", ErrSignerMismatch, signed.Value(), tx.Value())
+	case !bytes.Equal(signed.Data(), tx.Data()):
+		return fmt.Errorf("%w: calldata changed", ErrSignerMismatch)
+	case signed.Gas() != tx.Gas():
+		return fmt.Errorf("%w: gas %d, expected %d", ErrSignerMismatch, signed.Gas(), tx.Gas())
+	case signed.GasPrice().Cmp(tx.GasPrice()) != 0,
+		signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0,
+		signed.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return fmt.Errorf("%w: fee changed to %s (tip %s), expected %s (tip %s)", ErrSignerMismatch,
+			signed.GasFeeCap(), signed.GasTipCap(), tx.GasFeeCap(), tx.GasTipCap())
+	case !sameAccessList(signed.AccessList(), tx.AccessList()):
+		return fmt.Errorf("%w: access list changed", ErrSignerMismatch)
+	}
+	return nil
+}
+
+// sameAccessList reports whether a and b list the same storage keys of the
+// same addresses in the same order.  A nil list equals an empty one.
+func sameAccessList(a, b types.AccessList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || len(a[i].StorageKeys) != len(b[i].StorageKeys) {
+			return false
+		}
+		for j := range a[i].StorageKeys {
+			if a[i].StorageKeys[j] != b[i].StorageKeys[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sameRecipient reports whether a and b are both contract creations or
+// both calls to the same address.
+func sameRecipient(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// SignTypedData sends data as JSON, which clef shows to its owner field by
+// field before signing.
+func (s *remoteSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encoding typed data: %w", err)
+	}
+	sig, err := s.signer.SignData(s.account, accounts.MimetypeTypedData, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("external signer: %w", err)
+	}
+	return sig, nil
+}
+
+// Close is a no-op: the signer holds no secrets in this process.
+func (s *remoteSigner) Close() {}
+
+// transactOpts returns options that sign with signer for chainID.  It
+// fails with ErrNotReplayProtected if chainID is missing, since signing
+// would then fall back to the unprotected Homestead scheme.
+func transactOpts(signer txSigner, chainID *big.Int) (*bind.TransactOpts, error) {
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: chain id %v", ErrNotReplayProtected, chainID)
+	}
+	from := signer.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signProtected(signer, tx, chainID)
+		},
+	}, nil
+}
+
+// signProtected signs tx with signer and checks that the result carries
+// EIP-155 replay protection for chainID.  An external signer in particular
+// may sign with a scheme of its own choosing.
+func signProtected(signer txSigner, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReplayProtected(signed, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// checkReplayProtected reports ErrNotReplayProtected unless tx is signed
+// for chainID.  Typed transactions always carry their chain ID; legacy
+// ones only do when signed under EIP-155, which encodes it in V.
+func checkReplayProtected(tx *types.Transaction, chainID *big.Int) error {
+	if !tx.Protected() {
+		return fmt.Errorf("%w: signed without a chain id", ErrNotReplayProtected)
+	}
+	if got := tx.ChainId(); got.Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: signed for chain %s, expected %s", ErrNotReplayProtected, got, chainID)
+	}
+	return nil
+}
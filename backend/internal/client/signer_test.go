@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+func TestCheckSigned(t *testing.T) {
+	chainID := big.NewInt(1337)
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := mockContract
+	elsewhere := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+	request := &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     3,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Gas:       30000,
+		GasFeeCap: big.NewInt(2e9),
+		GasTipCap: big.NewInt(1e9),
+		Data:      []byte{0x60, 0xfe, 0x47, 0xb1},
+	}
+	tx := types.NewTx(request)
+	sign := func(key *ecdsa.PrivateKey, change func(*types.DynamicFeeTx)) *types.Transaction {
+		inner := *request
+		if change != nil {
+			change(&inner)
+		}
+		signed, err := types.SignNewTx(key, types.LatestSignerForChainID(inner.ChainID), &inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	// The same fields as a legacy transaction, priced at the fee cap.
+	signLegacy := func(key *ecdsa.PrivateKey) *types.Transaction {
+		signed, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+			Nonce: request.Nonce, To: request.To, Value: request.Value, Gas: request.Gas,
+			GasPrice: request.GasFeeCap, Data: request.Data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	if err := checkSigned(tx, sign(key, nil), from, chainID); err != nil {
+		t.Errorf("checkSigned of a faithful signature = %v", err)
+	}
+	tests := []struct {
+		name   string
+		signed *types.Transaction
+	}{
+		{"other account", sign(other, nil)},
+		{"other chain", sign(key, func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) })},
+		{"nonce", sign(key, func(tx *types.DynamicFeeTx) { tx.Nonce++ })},
+		{"recipient", sign(key, func(tx *types.DynamicFeeTx) { tx.To = &elsewhere })},
+		{"creation", sign(key, func(tx *types.DynamicFeeTx) { tx.To = nil })},
+		{"value", sign(key, func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) })},
+		{"data", sign(key, func(tx *types.DynamicFeeTx) { tx.Data = []byte{0x60, 0xfe, 0x47, 0xb2} })},
+		{"gas", sign(key, func(tx *types.DynamicFeeTx) { tx.Gas = 21000 })},
+		{"fee changed", sign(key, func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(200e9) })},
+		{"tip changed", sign(key, func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(2e9) })},
+		{"access list", sign(key, func(tx *types.DynamicFeeTx) {
+			tx.AccessList = types.AccessList{{Address: elsewhere, StorageKeys: []common.Hash{{}}}}
+		})},
+		{"type changed", signLegacy(key)},
+	}
+	for _, tt := range tests {
+		if err := checkSigned(tx, tt.signed, from, chainID); !errors.Is(err, ErrSignerMismatch) {
+			t.Errorf("%s changed: checkSigned = %v, want ErrSignerMismatch", tt.name, err)
+		}
+	}
+}
+
+// schemeSigner signs with key under a scheme of its own choosing, as an
+// external signer might, whatever chain it is asked to sign for.
+type schemeSigner struct {
+	localSigner
+	scheme types.Signer
+}
+
+func (s *schemeSigner) SignTx(tx *types.Transaction, _ *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, s.scheme, s.key)
+}
+
+func TestSignProtected(t *testing.T) {
+	chainID := big.NewInt(1337)
+	key, _ := crypto.GenerateKey()
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, To: &mockContract, Gas: 30000, GasPrice: big.NewInt(1e9)})
+
+	tests := []struct {
+		name   string
+		scheme types.Signer
+		ok     bool
+	}{
+		{"homestead", types.HomesteadSigner{}, false},
+		{"eip155 other chain", types.NewEIP155Signer(big.NewInt(1)), false},
+		{"eip155", types.NewEIP155Signer(chainID), true},
+		{"latest", types.LatestSignerForChainID(chainID), true},
+	}
+	for _, tt := range tests {
+		signed, err := signProtected(&schemeSigner{localSigner{key: key}, tt.scheme}, tx, chainID)
+		if !tt.ok {
+			if !errors.Is(err, ErrNotReplayProtected) {
+				t.Errorf("%s: signProtected = %v, want ErrNotReplayProtected", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: signProtected = %v", tt.name, err)
+			continue
+		}
+		if got := signed.ChainId(); got.Cmp(chainID) != 0 {
+			t.Errorf("%s: chain id = %s, want %s", tt.name, got, chainID)
+		}
+	}
+}
+
+func TestCheckReplayProtectedTypedTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), To: &mockContract, Gas: 30000, GasFeeCap: big.NewInt(2e9), GasTipCap: big.NewInt(1e9),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkReplayProtected(tx, big.NewInt(1)); err != nil {
+		t.Errorf("checkReplayProtected for its own chain = %v", err)
+	}
+	if err := checkReplayProtected(tx, big.NewInt(1337)); !errors.Is(err, ErrNotReplayProtected) {
+		t.Errorf("checkReplayProtected for another chain = %v, want ErrNotReplayProtected", err)
+	}
+}
+
+func TestTransactOptsRequiresChainID(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	for _, chainID := range []*big.Int{nil, big.NewInt(0)} {
+		if _, err := transactOpts(&localSigner{key: key}, chainID); !errors.Is(err, ErrNotReplayProtected) {
+			t.Errorf("transactOpts(%v) = %v, want ErrNotReplayProtected", chainID, err)
+		}
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	hexKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    common.Address
+		wantErr error
+	}{
+		{"missing key", Config{}, common.Address{}, ErrNoSigningKey},
+		{"malformed hex", Config{PrivateKey: "zz" + hexKey[2:]}, common.Address{}, ErrInvalidKey},
+		{"short key", Config{PrivateKey: hexKey[:62]}, common.Address{}, ErrInvalidKey},
+		{"other address", Config{PrivateKey: hexKey, SignerAddress: mockContract.Hex()}, common.Address{}, ErrAccountNotFound},
+		{"valid key", Config{PrivateKey: hexKey}, from, nil},
+		{"read-only", Config{PrivateKey: hexKey, ReadOnly: true}, common.Address{}, nil},
+	}
+	for _, tt := range tests {
+		signer, err := newSigner(tt.cfg)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: newSigner = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := signer.Address(); got != tt.want {
+			t.Errorf("%s: address = %s, want %s", tt.name, got, tt.want)
+		}
+		signer.Close()
+	}
+}
+
+func TestGetTransactionAuthorizer(t *testing.T) {
+	down := errors.New("node down")
+	tests := []struct {
+		name    string
+		fail    string
+		cfg     Config
+		wantErr error
+	}{
+		{"chain id", "ChainID", Config{}, down},
+		{"nonce", "PendingNonceAt", Config{}, down},
+		{"read-only", "", Config{ReadOnly: true}, ErrReadOnly},
+	}
+	for _, tt := range tests {
+		m := newMockBackend(t)
+		tt.cfg.RetryAttempts = 1
+		c, _ := newMockClient(t, m, tt.cfg)
+		if tt.fail != "" {
+			m.failWith(tt.fail, down)
+		}
+		if _, err := c.getTransactionAuthorizer(context.Background()); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: getTransactionAuthorizer = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	m := newMockBackend(t)
+	c, key := newMockClient(t, m, Config{})
+	for want := int64(0); want < 2; want++ {
+		auth, err := c.getTransactionAuthorizer(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from := crypto.PubkeyToAddress(key.PublicKey); auth.From != from {
+			t.Errorf("From = %s, want %s", auth.From, from)
+		}
+		// Each call reserves the next nonce.
+		if auth.Nonce.Int64() != want {
+			t.Errorf("Nonce = %s, want %d", auth.Nonce, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/jumbochain/jumbochain-go/accounts/abi/bind/backends"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+// simulatedBin is SimpleStorage's creation code; see its .asm listing.
+const simulatedBin = "testdata/SimpleStorage.bin"
+
+// simBackend adapts an in-process simulated chain to ContractBackend.
+// Every transaction is mined in a block of its own as soon as it is sent.
+type simBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b simBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+func (b simBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.Blockchain().Config().ChainID), nil
+}
+
+func (b simBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.Blockchain().CurrentBlock().Number.Uint64(), nil
+}
+
+func (b simBackend) Close() {
+	b.SimulatedBackend.Close()
+}
+
+// newSimulatedClient deploys SimpleStorage holding initVal on a fresh
+// simulated chain, from an account funded in its genesis, and returns a
+// client bound to it that signs with that account.  cfg may adjust any
+// other setting.
+func newSimulatedClient(t testing.TB, initVal *big.Int, cfg Config) (*StorageClient, simBackend) {
+	t.Helper()
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	funds := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	backend := simBackend{backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: funds}}, 30_000_000)}
+
+	address := deploySimulated(t, backend, key, initVal)
+	cfg.PrivateKey = common.Bytes2Hex(crypto.FromECDSA(key))
+	cfg.ContractAddress = address.Hex()
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Millisecond
+	}
+	c, err := NewStorageClientWithBackend(ctx, cfg, backend)
+	if err != nil {
+		backend.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c, backend
+}
+
+// deploySimulated deploys the testdata contract holding initVal from key.
+func deploySimulated(t testing.TB, backend simBackend, key *ecdsa.PrivateKey, initVal *big.Int) common.Address {
+	t.Helper()
+	bytecode, err := LoadBytecode(simulatedBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID, _ := backend.ChainID(context.Background())
+	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _, err := deployStorage(context.Background(), backend, auth, bytecode, initVal, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+// wantValue fails t unless the contract holds want.
+func wantValue(t *testing.T, c *StorageClient, want int64) {
+	t.Helper()
+	got, err := c.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(big.NewInt(want)) != 0 {
+		t.Errorf("Get = %s, want %d", got, want)
+	}
+}
+
+func TestSimulatedSetGet(t *testing.T) {
+	c, _ := newSimulatedClient(t, big.NewInt(7), Config{})
+	wantValue(t, c, 7)
+
+	result, err := c.Set(context.Background(), big.NewInt(150))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Succeeded() || result.BlockNumber == 0 {
+		t.Errorf("result = %+v, want a mined transaction", result)
+	}
+	if len(result.Events) != 1 || result.Events[0].Event != "ValueChanged" {
+		t.Fatalf("events = %+v, want one ValueChanged", result.Events)
+	}
+	ev := result.Events[0].Fields
+	if ev["oldValue"].(*big.Int).Int64() != 7 || ev["newValue"].(*big.Int).Int64() != 150 || ev["setter"] != c.From() {
+		t.Errorf("ValueChanged = %v, want %s setting 7 to 150", ev, c.From())
+	}
+	wantValue(t, c, 150)
+}
+
+func TestSimulatedAdd(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newSimulatedClient(t, big.NewInt(10), Config{})
+
+	if _, err := c.Add(ctx, big.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	wantValue(t, c, 15)
+
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	_, err := c.Add(ctx, max)
+	var revert *RevertError
+	if !errors.As(err, &revert) || !strings.Contains(revert.Reason, "overflow") {
+		t.Errorf("Add overflowing uint256 = %v, want an overflow panic", err)
+	}
+	wantValue(t, c, 15)
+}
+
+func TestSimulatedSub(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newSimulatedClient(t, big.NewInt(10), Config{})
+
+	if _, err := c.Sub(ctx, big.NewInt(4)); err != nil {
+		t.Fatal(err)
+	}
+	wantValue(t, c, 6)
+
+	if _, err := c.Sub(ctx, big.NewInt(7)); !errors.Is(err, ErrUnderflow) {
+		t.Errorf("Sub below zero = %v, want ErrUnderflow", err)
+	}
+	wantValue(t, c, 6)
+}
+
+func BenchmarkGet(b *testing.B) {
+	ctx := context.Background()
+	c, _ := newSimulatedClient(b, big.NewInt(7), Config{})
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.Get(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSetEstimate(b *testing.B) {
+	ctx := context.Background()
+	c, _ := newSimulatedClient(b, big.NewInt(7), Config{})
+	value := big.NewInt(150)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.EstimateCost(ctx, "set", value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
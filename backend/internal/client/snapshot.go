@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// Snapshot is the state of the contract at one block, for backups.
+type Snapshot struct {
+	Address     common.Address
+	ChainID     *big.Int
+	Value       *big.Int
+	BlockNumber uint64    // block the value was read at
+	Time        time.Time // when the snapshot was taken
+}
+
+// Snapshot reads the stored value at the latest block together with the
+// metadata needed to tell later where it came from.
+func (c *StorageClient) Snapshot(ctx context.Context) (*Snapshot, error) {
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block, err := c.resolveToBlock(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	value, err := c.GetAtBlock(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Address:     c.address,
+		ChainID:     chainID,
+		Value:       value,
+		BlockNumber: block,
+		Time:        time.Now().UTC(),
+	}, nil
+}
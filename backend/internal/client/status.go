@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+)
+
+// TxState is where a transaction looked up by TxStatus stands.
+type TxState string
+
+const (
+	// TxNotFound means the node knows nothing of the transaction: it was
+	// never received, or has been dropped from the mempool.
+	TxNotFound TxState = "not_found"
+	TxPending  TxState = "pending"
+	TxMined    TxState = "mined"
+	TxFailed   TxState = "failed" // mined, but reverted
+)
+
+// TxStatus describes a transaction looked up by hash.  BlockNumber and
+// GasUsed are set once it is mined; RevertReason when it failed and the
+// reason could be recovered.
+type TxStatus struct {
+	TxHash       common.Hash
+	State        TxState
+	BlockNumber  uint64
+	GasUsed      uint64
+	RevertReason string
+}
+
+// TxStatus looks up the transaction with the given hash, such as one sent
+// with cfg.NoWait, and reports whether it is pending, mined or failed.
+// Unknown transactions are reported as TxNotFound rather than an error.
+func (c *StorageClient) TxStatus(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	status := &TxStatus{TxHash: hash}
+
+	receipt, err := c.Receipt(ctx, hash)
+	if err != nil && !errors.Is(err, jumbochain.NotFound) {
+		return nil, err
+	}
+	if receipt != nil {
+		status.State = TxMined
+		status.GasUsed = receipt.GasUsed
+		if receipt.BlockNumber != nil {
+			status.BlockNumber = receipt.BlockNumber.Uint64()
+		}
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			return status, nil
+		}
+		status.State = TxFailed
+	}
+
+	var tx *types.Transaction
+	err = c.retry(ctx, func() (err error) {
+		tx, _, err = c.client.TransactionByHash(ctx, hash)
+		return err
+	})
+	switch {
+	case errors.Is(err, jumbochain.NotFound) && status.State == "":
+		status.State = TxNotFound
+	case err != nil && status.State == "":
+		return nil, fmt.Errorf("fetching transaction %s: %w", hash.Hex(), err)
+	case err != nil:
+		// The receipt is enough; the reason is merely unknown.
+	case status.State == TxFailed:
+		status.RevertReason = c.revertReason(ctx, tx, receipt.BlockNumber)
+	default:
+		// Known to the node but without a receipt.  Even a transaction
+		// the node no longer reports as pending may still be indexing.
+		status.State = TxPending
+	}
+	return status, nil
+}
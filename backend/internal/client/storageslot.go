@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// ValueSlot is the storage slot of the contract's stored value, its first
+// and only state variable.
+var ValueSlot = common.Hash{}
+
+// StorageAt returns the raw 32 bytes of the contract's storage at slot,
+// read at blockNumber or the latest block if nil, bypassing the ABI.
+// Reading ValueSlot gives the stored value, big-endian, as Get should
+// return it.
+func (c *StorageClient) StorageAt(ctx context.Context, slot common.Hash, blockNumber *big.Int) ([]byte, error) {
+	var contents []byte
+	err := c.retry(ctx, func() (err error) {
+		contents, err = c.client.StorageAt(ctx, c.address, slot, blockNumber)
+		return err
+	})
+	if err != nil {
+		if isMissingState(err) {
+			return nil, fmt.Errorf("reading slot %s at block %s: %w: %w", slot.Hex(), blockNumber, ErrStateUnavailable, err)
+		}
+		return nil, fmt.Errorf("reading slot %s: %w", slot.Hex(), err)
+	}
+	return contents, nil
+}
@@ -0,0 +1,336 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	jumbochain "github.com/jumbochain/jumbochain-go"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/rpc"
+)
+
+const (
+	reconnectBackoff    = time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// ConnState describes the client's connection to the node.
+type ConnState int
+
+const (
+	// ConnConnected means every connection is up, as far as the client
+	// knows.
+	ConnConnected ConnState = iota
+	// ConnReconnecting means a connection was lost and is being re-dialed.
+	// Requests fail until it is back.
+	ConnReconnecting
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case ConnConnected:
+		return "connected"
+	case ConnReconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
+
+// supervisedBackend wraps a websocket or IPC connection and re-dials it in
+// the background, with backoff, once a request or subscription shows that
+// it has been lost.  Requests made while reconnecting go to the old
+// connection and fail; the caller's retry policy covers the gap.
+// Subscriptions on the old connection end with an error when it is
+// replaced, and WatchValueChanged re-establishes them over the new one.
+type supervisedBackend struct {
+	url    string
+	logger *slog.Logger
+	dial   func(ctx context.Context) (ContractBackend, error)
+
+	ctx    context.Context // cancelled by Close to stop reconnecting
+	cancel context.CancelFunc
+
+	mu           sync.Mutex
+	conn         ContractBackend
+	reconnecting bool
+}
+
+// dialSupervised is like dialBackend, but for websocket and IPC urls the
+// connection is supervised so it is re-dialed when lost.  The returned
+// supervisor is nil for http urls, whose requests each open their own
+// connection anyway.
+func dialSupervised(ctx context.Context, cfg Config, url string) (ContractBackend, *supervisedBackend, error) {
+	client, err := dialBackend(ctx, cfg, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isHTTP(url) {
+		return client, nil, nil
+	}
+	b := &supervisedBackend{
+		url:    url,
+		logger: cfg.Logger,
+		dial: func(ctx context.Context) (ContractBackend, error) {
+			return dialClient(ctx, cfg, url)
+		},
+		conn: client,
+	}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	return b, b, nil
+}
+
+// State reports whether the connection is up or being re-dialed.
+func (b *supervisedBackend) State() ConnState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reconnecting {
+		return ConnReconnecting
+	}
+	return ConnConnected
+}
+
+func (b *supervisedBackend) current() ContractBackend {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conn
+}
+
+// check starts reconnecting if err shows that conn has been lost.
+func (b *supervisedBackend) check(conn ContractBackend, err error) {
+	if err != nil && isConnectionLost(err) {
+		b.lost(conn, err)
+	}
+}
+
+// lost starts re-dialing in the background, unless conn has already been
+// replaced or a reconnect is under way.
+func (b *supervisedBackend) lost(conn ContractBackend, err error) {
+	b.mu.Lock()
+	if b.ctx.Err() != nil || b.reconnecting || b.conn != conn {
+		b.mu.Unlock()
+		return
+	}
+	b.reconnecting = true
+	b.mu.Unlock()
+
+	b.logger.Warn("connection to node lost, reconnecting", "url", redactURL(b.url), "err", err)
+	go b.reconnect()
+}
+
+// reconnect re-dials until it succeeds or the backend is closed, then
+// swaps the new connection in and closes the old one.
+func (b *supervisedBackend) reconnect() {
+	backoff := reconnectBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := b.dial(b.ctx)
+		if err == nil {
+			b.mu.Lock()
+			if b.ctx.Err() != nil {
+				b.mu.Unlock()
+				conn.Close()
+				return
+			}
+			old := b.conn
+			b.conn = conn
+			b.reconnecting = false
+			b.mu.Unlock()
+
+			old.Close()
+			b.logger.Info("reconnected to node", "url", redactURL(b.url), "attempts", attempt)
+			return
+		}
+		b.logger.Warn("reconnecting to node failed", "url", redactURL(b.url), "err", err, "retryIn", backoff)
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+}
+
+// Close stops any reconnect in progress and closes the connection.
+func (b *supervisedBackend) Close() {
+	b.cancel()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conn.Close()
+}
+
+// isConnectionLost reports whether err means the connection itself is
+// gone, as opposed to a single request failing or timing out.
+func isConnectionLost(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return !opErr.Timeout()
+	}
+	return errors.Is(err, rpc.ErrClientQuit) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// supervisedSub passes on the errors of a subscription, first reporting
+// them to the backend: a subscription only fails when its connection does.
+type supervisedSub struct {
+	jumbochain.Subscription
+	errc chan error
+}
+
+func (s *supervisedSub) Err() <-chan error {
+	return s.errc
+}
+
+func (b *supervisedBackend) SubscribeFilterLogs(ctx context.Context, query jumbochain.FilterQuery, ch chan<- types.Log) (jumbochain.Subscription, error) {
+	conn := b.current()
+	sub, err := conn.SubscribeFilterLogs(ctx, query, ch)
+	if err != nil {
+		b.check(conn, err)
+		return nil, err
+	}
+	s := &supervisedSub{Subscription: sub, errc: make(chan error, 1)}
+	go func() {
+		defer close(s.errc)
+		if err, ok := <-sub.Err(); ok && err != nil {
+			b.lost(conn, err)
+			s.errc <- err
+		}
+	}()
+	return s, nil
+}
+
+func (b *supervisedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	conn := b.current()
+	v, err := conn.CodeAt(ctx, contract, blockNumber)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) CallContract(ctx context.Context, call jumbochain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	conn := b.current()
+	v, err := conn.CallContract(ctx, call, blockNumber)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) PendingCallContract(ctx context.Context, call jumbochain.CallMsg) ([]byte, error) {
+	conn := b.current()
+	v, err := conn.PendingCallContract(ctx, call)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	conn := b.current()
+	v, err := conn.HeaderByNumber(ctx, number)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	conn := b.current()
+	v, err := conn.PendingCodeAt(ctx, account)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	conn := b.current()
+	v, err := conn.PendingNonceAt(ctx, account)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	conn := b.current()
+	v, err := conn.SuggestGasPrice(ctx)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	conn := b.current()
+	v, err := conn.SuggestGasTipCap(ctx)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) EstimateGas(ctx context.Context, call jumbochain.CallMsg) (uint64, error) {
+	conn := b.current()
+	v, err := conn.EstimateGas(ctx, call)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	conn := b.current()
+	err := conn.SendTransaction(ctx, tx)
+	b.check(conn, err)
+	return err
+}
+
+func (b *supervisedBackend) FilterLogs(ctx context.Context, query jumbochain.FilterQuery) ([]types.Log, error) {
+	conn := b.current()
+	v, err := conn.FilterLogs(ctx, query)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	conn := b.current()
+	v, err := conn.TransactionReceipt(ctx, txHash)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	conn := b.current()
+	v, err := conn.ChainID(ctx)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	conn := b.current()
+	v, err := conn.BlockNumber(ctx)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	conn := b.current()
+	tx, pending, err := conn.TransactionByHash(ctx, hash)
+	b.check(conn, err)
+	return tx, pending, err
+}
+
+func (b *supervisedBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	conn := b.current()
+	v, err := conn.BalanceAt(ctx, account, blockNumber)
+	b.check(conn, err)
+	return v, err
+}
+
+func (b *supervisedBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	conn := b.current()
+	v, err := conn.StorageAt(ctx, account, key, blockNumber)
+	b.check(conn, err)
+	return v, err
+}
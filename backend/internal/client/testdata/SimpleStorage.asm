@@ -0,0 +1,217 @@
+; SimpleStorage.bin, annotated.
+;
+; The build checks in only the contract's ABI, so the tests deploy this
+; hand-assembled equivalent of contracts/SimpleStorage.sol instead of solc
+; output.  It keeps the contract's storage layout, ABI, events and
+; reverts:
+;
+;   - storedData is slot 0, and the constructor stores its uint256
+;     argument there.
+;   - set, add and sub emit ValueChanged(msg.sender indexed, old, new).
+;   - add reverts with Panic(0x11) on overflow.
+;   - sub reverts with Error("SimpleStorage: underflow") when x exceeds
+;     storedData.
+;   - add and sub return the new value.
+;   - Any call with value, unknown selector or short calldata reverts
+;     without data.
+;
+; Its runtime part, from offset 0x0030, is ../SimpleStorage.bin-runtime,
+; the code VerifyBytecode expects unless EXPECTED_CODE_HASH says
+; otherwise.  Its hash is not that of a solc build.
+;
+; Offsets are hexadecimal, PUSH2 operands name the label or value they
+; stand for, and stack comments list the top last.
+
+; creation code
+0000  CALLVALUE                     ; constructor is not payable
+0001  PUSH2 0x002b (@crevert)
+0004  JUMPI
+0005  PUSH2 0x01ed (#argsEnd)
+0008  CODESIZE
+0009  LT
+000a  PUSH2 0x002b (@crevert)
+000d  JUMPI                         ; initVal missing
+000e  PUSH1 0x20
+0010  PUSH2 0x01cd (#total)
+0013  PUSH1 0x00
+0015  CODECOPY                      ; initVal
+0016  PUSH1 0x00
+0018  MLOAD
+0019  PUSH1 0x00
+001b  SSTORE                        ; storedData = initVal
+001c  PUSH2 0x019d (#rtlen)
+001f  PUSH2 0x0030 (#rtoff)
+0022  PUSH1 0x00
+0024  CODECOPY
+0025  PUSH2 0x019d (#rtlen)
+0028  PUSH1 0x00
+002a  RETURN
+002b  crevert: JUMPDEST
+002c  PUSH1 0x00
+002e  DUP1
+002f  REVERT
+
+; runtime code, copied from offset 0x0030
+0000  CALLVALUE                     ; no function is payable
+0001  PUSH2 0x003f (@revert)
+0004  JUMPI
+0005  PUSH1 0x04
+0007  CALLDATASIZE
+0008  LT
+0009  PUSH2 0x003f (@revert)
+000c  JUMPI                         ; no fallback
+000d  PUSH1 0x00
+000f  CALLDATALOAD
+0010  PUSH1 0xe0
+0012  SHR                           ; selector
+0013  DUP1
+0014  PUSH4 0x6d4ce63c              ; get()
+0019  EQ
+001a  PUSH2 0x0044 (@get)
+001d  JUMPI
+001e  DUP1
+001f  PUSH4 0x60fe47b1              ; set(uint256)
+0024  EQ
+0025  PUSH2 0x0050 (@set)
+0028  JUMPI
+0029  DUP1
+002a  PUSH4 0x1003e2d2              ; add(uint256)
+002f  EQ
+0030  PUSH2 0x0061 (@add)
+0033  JUMPI
+0034  DUP1
+0035  PUSH4 0x27ee58a6              ; sub(uint256)
+003a  EQ
+003b  PUSH2 0x007f (@sub)
+003e  JUMPI
+003f  revert: JUMPDEST
+0040  PUSH1 0x00
+0042  DUP1
+0043  REVERT
+0044  get: JUMPDEST
+0045  PUSH1 0x00
+0047  SLOAD
+0048  PUSH1 0x00
+004a  MSTORE
+004b  PUSH1 0x20
+004d  PUSH1 0x00
+004f  RETURN
+0050  set: JUMPDEST
+0051  PUSH2 0x0058 (@set_body)
+0054  PUSH2 0x009d (@arg)
+0057  JUMP
+0058  set_body: JUMPDEST
+0059  DUP2                          ; x
+005a  PUSH1 0x00
+005c  SSTORE
+005d  PUSH2 0x00af (@emit)
+0060  JUMP                          ; emit(old, x)
+0061  add: JUMPDEST
+0062  PUSH2 0x0069 (@add_body)
+0065  PUSH2 0x009d (@arg)
+0068  JUMP
+0069  add_body: JUMPDEST
+006a  DUP2                          ; x
+006b  DUP2                          ; old
+006c  ADD
+006d  DUP1
+006e  DUP3                          ; old
+006f  GT
+0070  PUSH2 0x0116 (@overflow)
+0073  JUMPI                         ; old > old+x
+0074  SWAP2
+0075  POP
+0076  SWAP1
+0077  DUP1
+0078  PUSH1 0x00
+007a  SSTORE                        ; storedData = new; stack [sel old new]
+007b  PUSH2 0x00de (@emit_return)
+007e  JUMP
+007f  sub: JUMPDEST
+0080  PUSH2 0x0087 (@sub_body)
+0083  PUSH2 0x009d (@arg)
+0086  JUMP
+0087  sub_body: JUMPDEST
+0088  DUP1                          ; old
+0089  DUP3                          ; x
+008a  GT
+008b  PUSH2 0x0145 (@underflow)
+008e  JUMPI                         ; x > old
+008f  DUP2                          ; x
+0090  DUP2                          ; old
+0091  SUB                           ; old - x
+0092  SWAP2
+0093  POP
+0094  SWAP1
+0095  DUP1
+0096  PUSH1 0x00
+0098  SSTORE                        ; storedData = new; stack [sel old new]
+0099  PUSH2 0x00de (@emit_return)
+009c  JUMP
+009d  arg: JUMPDEST
+009e  PUSH1 0x24
+00a0  CALLDATASIZE
+00a1  LT
+00a2  PUSH2 0x003f (@revert)
+00a5  JUMPI                         ; short calldata
+00a6  PUSH1 0x04
+00a8  CALLDATALOAD
+00a9  PUSH1 0x00
+00ab  SLOAD
+00ac  SWAP1                         ; stack [sel ret old x]
+00ad  SWAP2                         ; stack [sel x old ret]
+00ae  JUMP                          ; stack [sel x old]
+00af  emit: JUMPDEST
+00b0  PUSH1 0x00
+00b2  MSTORE                        ; old
+00b3  PUSH1 0x20
+00b5  MSTORE                        ; new
+00b6  CALLER
+00b7  PUSH32 0xe435f0fbe584e62b62f48f4016a57ef6c95e4c79f5babbe6ad3bb64f3281d261; ValueChanged
+00d8  PUSH1 0x40
+00da  PUSH1 0x00
+00dc  LOG2
+00dd  STOP
+00de  emit_return: JUMPDEST
+00df  SWAP1
+00e0  PUSH1 0x00
+00e2  MSTORE                        ; old
+00e3  DUP1
+00e4  PUSH1 0x20
+00e6  MSTORE                        ; new
+00e7  CALLER
+00e8  PUSH32 0xe435f0fbe584e62b62f48f4016a57ef6c95e4c79f5babbe6ad3bb64f3281d261; ValueChanged
+0109  PUSH1 0x40
+010b  PUSH1 0x00
+010d  LOG2
+010e  PUSH1 0x00
+0110  MSTORE
+0111  PUSH1 0x20
+0113  PUSH1 0x00
+0115  RETURN
+0116  overflow: JUMPDEST
+0117  PUSH32 0x4e487b7100000000000000000000000000000000000000000000000000000000; Panic(uint256)
+0138  PUSH1 0x00
+013a  MSTORE
+013b  PUSH1 0x11                    ; arithmetic overflow
+013d  PUSH1 0x04
+013f  MSTORE
+0140  PUSH1 0x24
+0142  PUSH1 0x00
+0144  REVERT
+0145  underflow: JUMPDEST
+0146  PUSH32 0x08c379a000000000000000000000000000000000000000000000000000000000; Error(string)
+0167  PUSH1 0x00
+0169  MSTORE
+016a  PUSH1 0x20
+016c  PUSH1 0x04
+016e  MSTORE
+016f  PUSH1 0x18
+0171  PUSH1 0x24
+0173  MSTORE
+0174  PUSH32 0x53696d706c6553746f726167653a20756e646572666c6f770000000000000000; SimpleStorage: underflow
+0195  PUSH1 0x44
+0197  MSTORE
+0198  PUSH1 0x64
+019a  PUSH1 0x00
+019c  REVERT
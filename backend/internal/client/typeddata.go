@@ -0,0 +1,135 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jumbochain/jumbochain-go/signer/core/apitypes"
+)
+
+// eip712Domain is the name EIP-712 reserves for the domain's type.
+const eip712Domain = "EIP712Domain"
+
+// ErrInvalidTypedData is returned when typed data cannot be signed because
+// its types are malformed.
+var ErrInvalidTypedData = errors.New("invalid typed data")
+
+// SignTypedData signs message under EIP-712 with the client's account and
+// returns the 65-byte signature R || S || V, with V being 27 or 28 as
+// eth_signTypedData returns it.  The primary type is the one type in types
+// that no other type refers to, and the EIP712Domain type is derived from
+// the fields set in domain unless types already declares it.  For example:
+//
+//	sig, err := c.SignTypedData(apitypes.TypedDataDomain{
+//		Name:              "SimpleStorage",
+//		Version:           "1",
+//		ChainId:           math.NewHexOrDecimal256(1337),
+//		VerifyingContract: c.Address().Hex(),
+//	}, apitypes.Types{
+//		"Set": {{Name: "value", Type: "uint256"}, {Name: "nonce", Type: "uint256"}},
+//	}, apitypes.TypedDataMessage{
+//		"value": "42",
+//		"nonce": "0",
+//	})
+func (c *StorageClient) SignTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) ([]byte, error) {
+	data, err := newTypedData(domain, types, message)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := c.signer.SignTypedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("signing %s: %w", data.PrimaryType, err)
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("signing %s: signer returned %d bytes, expected 65", data.PrimaryType, len(sig))
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
+
+// newTypedData assembles the typed data SignTypedData signs.  types is
+// copied, not modified.
+func newTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, message apitypes.TypedDataMessage) (apitypes.TypedData, error) {
+	all := make(apitypes.Types, len(types)+1)
+	for name, fields := range types {
+		all[name] = fields
+	}
+	if _, ok := all[eip712Domain]; !ok {
+		all[eip712Domain] = domainType(domain)
+	}
+	primary, err := primaryType(all)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	return apitypes.TypedData{
+		Types:       all,
+		PrimaryType: primary,
+		Domain:      domain,
+		Message:     message,
+	}, nil
+}
+
+// domainType lists the fields of domain that are set, in the order
+// EIP-712 gives them.
+func domainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// primaryType returns the one type other than EIP712Domain that no field
+// of another type refers to.
+func primaryType(types apitypes.Types) (string, error) {
+	referenced := make(map[string]bool)
+	for name, fields := range types {
+		for _, field := range fields {
+			if field.Type != name {
+				referenced[baseType(field.Type)] = true
+			}
+		}
+	}
+	var candidates []string
+	for name := range types {
+		if name != eip712Domain && !referenced[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		return "", fmt.Errorf("%w: no primary type", ErrInvalidTypedData)
+	default:
+		sort.Strings(candidates)
+		return "", fmt.Errorf("%w: ambiguous primary type, one of %v", ErrInvalidTypedData, candidates)
+	}
+}
+
+// baseType strips array suffixes such as "[]" or "[3]" from a field type.
+func baseType(typ string) string {
+	for len(typ) > 0 && typ[len(typ)-1] == ']' {
+		i := len(typ) - 1
+		for i > 0 && typ[i] != '[' {
+			i--
+		}
+		typ = typ[:i]
+	}
+	return typ
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/common/math"
+	"github.com/jumbochain/jumbochain-go/crypto"
+	"github.com/jumbochain/jumbochain-go/signer/core/apitypes"
+)
+
+// The Mail example of EIP-712, signed by the key keccak256("cow").
+var (
+	mailDomain = apitypes.TypedDataDomain{
+		Name:              "Ether Mail",
+		Version:           "1",
+		ChainId:           math.NewHexOrDecimal256(1),
+		VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+	}
+	mailTypes = apitypes.Types{
+		"Person": {{Name: "name", Type: "string"}, {Name: "wallet", Type: "address"}},
+		"Mail":   {{Name: "from", Type: "Person"}, {Name: "to", Type: "Person"}, {Name: "contents", Type: "string"}},
+	}
+	mailMessage = apitypes.TypedDataMessage{
+		"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!",
+	}
+	mailSigner = common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	mailHash   = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	mailSig    = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+)
+
+func ExampleRecoverTypedData() {
+	data := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": mailTypes["Person"],
+			"Mail":   mailTypes["Mail"],
+		},
+		PrimaryType: "Mail",
+		Domain:      mailDomain,
+		Message:     mailMessage,
+	}
+	sig := hexutil.MustDecode(mailSig)
+
+	signer, err := RecoverTypedData(data, sig)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(data.PrimaryType, "signed by", signer.Hex())
+	// Output: Mail signed by 0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826
+}
+
+func TestSignTypedDataMailVector(t *testing.T) {
+	m := newMockBackend(t)
+	c, err := NewStorageClientWithBackend(context.Background(), Config{
+		PrivateKey:      common.Bytes2Hex(crypto.Keccak256([]byte("cow"))),
+		ContractAddress: mockContract.Hex(),
+	}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	data, err := newTypedData(mailDomain, mailTypes, mailMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.PrimaryType != "Mail" {
+		t.Errorf("primary type = %q, want Mail", data.PrimaryType)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(hash); got != mailHash {
+		t.Errorf("hash = %s, want %s", got, mailHash)
+	}
+
+	sig, err := c.SignTypedData(mailDomain, mailTypes, mailMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(sig); got != mailSig {
+		t.Errorf("signature = %s, want %s", got, mailSig)
+	}
+
+	// Recovery accepts V as 27 or 28, as signed, and as 0 or 1.
+	for _, v := range []byte{sig[64], sig[64] - 27} {
+		sig := bytes.Clone(sig)
+		sig[64] = v
+		signer, err := RecoverTypedData(data, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer != mailSigner {
+			t.Errorf("RecoverTypedData with v=%d = %s, want %s", v, signer, mailSigner)
+		}
+	}
+
+	// A signature over other contents recovers someone else.
+	data.Message = apitypes.TypedDataMessage{"from": mailMessage["from"], "to": mailMessage["to"], "contents": "Hello, Alice!"}
+	if signer, err := RecoverTypedData(data, sig); err == nil && signer == mailSigner {
+		t.Error("RecoverTypedData of altered contents recovered the signer")
+	}
+}
+
+func TestPrimaryType(t *testing.T) {
+	tests := []struct {
+		name  string
+		types apitypes.Types
+		want  string
+	}{
+		{"nested", mailTypes, "Mail"},
+		{"array", apitypes.Types{
+			"Group":  {{Name: "members", Type: "Member[]"}},
+			"Member": {{Name: "wallet", Type: "address"}},
+		}, "Group"},
+		{"recursive", apitypes.Types{"Node": {{Name: "next", Type: "Node"}}}, "Node"},
+		{"none", apitypes.Types{
+			"A": {{Name: "b", Type: "B"}},
+			"B": {{Name: "a", Type: "A"}},
+		}, ""},
+		{"ambiguous", apitypes.Types{
+			"A": {{Name: "x", Type: "uint256"}},
+			"B": {{Name: "y", Type: "uint256"}},
+		}, ""},
+	}
+	for _, tt := range tests {
+		got, err := primaryType(tt.types)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidTypedData) {
+				t.Errorf("%s: primaryType = %q, %v, want ErrInvalidTypedData", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: primaryType = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/jumbochain/jumbochain-go/accounts"
+	"github.com/jumbochain/jumbochain-go/accounts/usbwallet"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/core/types"
+	"github.com/jumbochain/jumbochain-go/signer/core/apitypes"
+)
+
+// SignerHardware is the value of cfg.Signer that selects a USB hardware
+// wallet.
+const SignerHardware = "hardware"
+
+// defaultSignerTimeout is how long a hardware wallet is given to have a
+// transaction approved when no timeout is configured.
+const defaultSignerTimeout = 2 * time.Minute
+
+// ErrNoHardwareWallet is returned when cfg.Signer is "hardware" but no
+// Ledger or Trezor is plugged in.
+var ErrNoHardwareWallet = errors.New("no hardware wallet found")
+
+// ErrSignerTimeout is returned when a transaction was not approved on the
+// hardware wallet within cfg.SignerTimeout.
+var ErrSignerTimeout = errors.New("timed out waiting for approval on the hardware wallet")
+
+// hardwareSigner signs on a Ledger or Trezor, which asks its owner to
+// approve every transaction on the device.
+type hardwareSigner struct {
+	wallet  accounts.Wallet
+	account accounts.Account
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// newHardwareSigner opens the first Ledger or Trezor found and derives the
+// account at cfg.DerivationPath and cfg.AccountIndex on it.  If
+// cfg.SignerAddress is set, the derived account must match it.
+func newHardwareSigner(cfg Config) (*hardwareSigner, error) {
+	path, err := derivationPath(cfg.DerivationPath, cfg.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallets []accounts.Wallet
+	for _, newHub := range []func() (*usbwallet.Hub, error){
+		usbwallet.NewLedgerHub,
+		usbwallet.NewTrezorHubWithHID,
+		usbwallet.NewTrezorHubWithWebUSB,
+	} {
+		hub, err := newHub()
+		if err != nil {
+			cfg.Logger.Debug("hardware wallet support unavailable", "err", err)
+			continue
+		}
+		wallets = append(wallets, hub.Wallets()...)
+	}
+	if len(wallets) == 0 {
+		return nil, ErrNoHardwareWallet
+	}
+	wallet := wallets[0]
+
+	cfg.Logger.Warn("unlock your hardware wallet and open its Ethereum app", "wallet", wallet.URL())
+	if err := wallet.Open(""); err != nil {
+		return nil, fmt.Errorf("opening hardware wallet %s: %w", wallet.URL(), err)
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		wallet.Close()
+		return nil, fmt.Errorf("deriving %s on hardware wallet: %w", path, err)
+	}
+	if cfg.SignerAddress != "" && account.Address != common.HexToAddress(cfg.SignerAddress) {
+		wallet.Close()
+		return nil, fmt.Errorf("hardware wallet account at %s is %s, not SIGNER_ADDRESS %s", path, account.Address.Hex(), cfg.SignerAddress)
+	}
+	return &hardwareSigner{wallet: wallet, account: account, timeout: cfg.SignerTimeout, logger: cfg.Logger}, nil
+}
+
+func (s *hardwareSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx asks the owner to approve tx on the device and waits up to
+// s.timeout for them to do so.
+func (s *hardwareSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	s.logger.Warn("confirm the transaction on your hardware wallet",
+		"to", tx.To(), "nonce", tx.Nonce(), "timeout", s.timeout)
+	var signed *types.Transaction
+	err := s.await(func() (err error) {
+		signed, err = s.wallet.SignTx(s.account, tx, chainID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// SignTypedData asks the owner to approve data on the device.  Only
+// Ledger supports EIP-712; a Trezor rejects the request.
+func (s *hardwareSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	_, raw, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("hashing typed data: %w", err)
+	}
+	s.logger.Warn("confirm the signature on your hardware wallet",
+		"type", data.PrimaryType, "timeout", s.timeout)
+	var sig []byte
+	err = s.await(func() (err error) {
+		sig, err = s.wallet.SignData(s.account, accounts.MimetypeTypedData, []byte(raw))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// await runs sign, which waits on the device, for up to s.timeout.  The
+// device cannot be told to stop asking, so after a timeout the prompt may
+// still be showing; rejecting it there is safe.
+func (s *hardwareSigner) await(sign func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- sign() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("hardware wallet: %w", err)
+		}
+		return nil
+	case <-time.After(s.timeout):
+		return fmt.Errorf("%w after %s", ErrSignerTimeout, s.timeout)
+	}
+}
+
+// Close releases the device.
+func (s *hardwareSigner) Close() {
+	s.wallet.Close()
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// Validate checks that cfg has everything needed to connect to an existing
+// contract and that each setting is well formed.  Every problem found is
+// reported at once, joined into a single error.
+func (cfg Config) Validate() error {
+	return cfg.validate(true)
+}
+
+// ValidateForDeploy is like Validate but does not require a contract
+// address, since Deploy creates the contract.
+func (cfg Config) ValidateForDeploy() error {
+	return cfg.validate(false)
+}
+
+func (cfg Config) validate(needContract bool) error {
+	var errs []error
+
+	if cfg.RPCURL == "" {
+		errs = append(errs, errors.New("RPC_URL is not set"))
+	} else if err := validateRPCURL(cfg.RPCURL); err != nil {
+		errs = append(errs, fmt.Errorf("RPC_URL: %w", err))
+	}
+	if cfg.WSURL != "" && !isWebsocket(cfg.WSURL) {
+		errs = append(errs, fmt.Errorf("WS_URL %q must use the ws:// or wss:// scheme", redactURL(cfg.WSURL)))
+	}
+
+	if needContract {
+		if cfg.ContractAddress == "" {
+			if !cfg.AutoDeploy {
+				errs = append(errs, errors.New("CONTRACT_ADDRESS is not set"))
+			}
+		} else if _, err := ParseAddress(cfg.ContractAddress); err != nil {
+			errs = append(errs, fmt.Errorf("CONTRACT_ADDRESS: %w", err))
+		}
+	}
+
+	switch {
+	case cfg.ReadOnly:
+		// No key is loaded, so none is needed.
+	case cfg.Signer != "" && cfg.Signer != SignerHardware:
+		errs = append(errs, fmt.Errorf("SIGNER %q is not supported, only %q", cfg.Signer, SignerHardware))
+	case cfg.Signer == SignerHardware:
+		if _, err := derivationPath(cfg.DerivationPath, cfg.AccountIndex); err != nil {
+			errs = append(errs, fmt.Errorf("DERIVATION_PATH: %w", err))
+		}
+		if cfg.SignerAddress != "" && !common.IsHexAddress(cfg.SignerAddress) {
+			errs = append(errs, fmt.Errorf("SIGNER_ADDRESS %q is not an address", cfg.SignerAddress))
+		}
+		if cfg.SignerTimeout < 0 {
+			errs = append(errs, fmt.Errorf("SIGNER_TIMEOUT must not be negative, got %s", cfg.SignerTimeout))
+		}
+	case cfg.SignerURL != "":
+		if _, err := url.Parse(cfg.SignerURL); err != nil {
+			errs = append(errs, fmt.Errorf("SIGNER_URL: %w", redactURLError(err)))
+		}
+		if cfg.SignerAddress != "" && !common.IsHexAddress(cfg.SignerAddress) {
+			errs = append(errs, fmt.Errorf("SIGNER_ADDRESS %q is not an address", cfg.SignerAddress))
+		}
+	case cfg.SignerAddress != "" && !common.IsHexAddress(cfg.SignerAddress):
+		errs = append(errs, fmt.Errorf("SIGNER_ADDRESS %q is not an address", cfg.SignerAddress))
+	case cfg.KeystorePath != "":
+		if _, err := keystoreFile(cfg.KeystorePath, cfg.SignerAddress, cfg.AccountIndex); err != nil {
+			errs = append(errs, fmt.Errorf("KEYSTORE_PATH: %w", err))
+		}
+	case cfg.Mnemonic != "":
+		key, err := loadSigningKey(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("MNEMONIC: %w", err))
+		}
+		zeroKey(key)
+	case cfg.PrivateKey != "":
+		key, err := loadSigningKey(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PRIVATE_KEY: %w", err))
+		}
+		zeroKey(key)
+	default:
+		errs = append(errs, fmt.Errorf("%w: set PRIVATE_KEY, MNEMONIC, KEYSTORE_PATH and KEYSTORE_PASSWORD, SIGNER_URL, or SIGNER=hardware", ErrNoSigningKey))
+	}
+
+	if cfg.ExpectedCodeHash != "" {
+		if _, err := ParseHash(cfg.ExpectedCodeHash); err != nil {
+			errs = append(errs, fmt.Errorf("EXPECTED_CODE_HASH: %w", err))
+		}
+	}
+	if cfg.MulticallAddress != "" && !common.IsHexAddress(cfg.MulticallAddress) {
+		errs = append(errs, fmt.Errorf("MULTICALL_ADDRESS %q is not an address", cfg.MulticallAddress))
+	}
+
+	if cfg.ChainID != nil && cfg.ChainID.Sign() <= 0 {
+		errs = append(errs, fmt.Errorf("CHAIN_ID must be positive, got %s", cfg.ChainID))
+	}
+	if cfg.ExpectedChainID != nil && cfg.ExpectedChainID.Sign() <= 0 {
+		errs = append(errs, fmt.Errorf("EXPECTED_CHAIN_ID must be positive, got %s", cfg.ExpectedChainID))
+	}
+	if cfg.GasPrice != nil && cfg.GasPrice.Sign() <= 0 {
+		errs = append(errs, fmt.Errorf("GAS_PRICE must be positive, got %s wei", cfg.GasPrice))
+	}
+	if cfg.RPCPoolSize < 0 {
+		errs = append(errs, fmt.Errorf("RPC_POOL_SIZE must not be negative, got %d", cfg.RPCPoolSize))
+	}
+	for name, values := range cfg.RPCHeaders {
+		if !validHeaderName(name) {
+			errs = append(errs, fmt.Errorf("RPC_HEADERS has an invalid header name %q", name))
+		}
+		for _, v := range values {
+			if strings.ContainsAny(v, "\r\n\x00") {
+				errs = append(errs, fmt.Errorf("RPC_HEADERS has an invalid value for header %s", name))
+			}
+		}
+	}
+	if cfg.GasOracleURL != "" {
+		if u, err := url.Parse(cfg.GasOracleURL); err != nil {
+			errs = append(errs, fmt.Errorf("GAS_ORACLE_URL: %w", redactURLError(err)))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			errs = append(errs, fmt.Errorf("GAS_ORACLE_URL %q must use the http:// or https:// scheme", redactURL(cfg.GasOracleURL)))
+		}
+	}
+	if _, err := ParseGasTier(string(cfg.GasTier)); err != nil {
+		errs = append(errs, fmt.Errorf("GAS_TIER: %w", err))
+	}
+	if cfg.GasPriceTTL < 0 {
+		errs = append(errs, fmt.Errorf("GAS_PRICE_TTL must not be negative, got %s", cfg.GasPriceTTL))
+	}
+	if cfg.MinValue != nil && cfg.MaxValue != nil && cfg.MinValue.Cmp(cfg.MaxValue) > 0 {
+		errs = append(errs, fmt.Errorf("MIN_VALUE %s is above MAX_VALUE %s", cfg.MinValue, cfg.MaxValue))
+	}
+	if cfg.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("RPC_RATE_LIMIT must not be negative, got %g", cfg.RateLimit))
+	}
+	if cfg.RetryAttempts < 0 {
+		errs = append(errs, fmt.Errorf("RETRY_ATTEMPTS must not be negative, got %d", cfg.RetryAttempts))
+	}
+	if cfg.BreakerThreshold < 0 {
+		errs = append(errs, fmt.Errorf("RPC_BREAKER_THRESHOLD must not be negative, got %d", cfg.BreakerThreshold))
+	}
+	if cfg.BreakerWindow < 0 {
+		errs = append(errs, fmt.Errorf("RPC_BREAKER_WINDOW must not be negative, got %s", cfg.BreakerWindow))
+	}
+	if cfg.BreakerCooldown < 0 {
+		errs = append(errs, fmt.Errorf("RPC_BREAKER_COOLDOWN must not be negative, got %s", cfg.BreakerCooldown))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
+
+// validateRPCURL checks that raw names a transport the client can dial:
+// http, https, ws, wss, or a path to an IPC socket.
+func validateRPCURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redactURLError(err)
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+		if u.Host == "" {
+			return fmt.Errorf("%q has no host", redactURL(raw))
+		}
+	case "":
+		// An IPC socket path.
+	default:
+		return fmt.Errorf("%q: unsupported scheme %q", redactURL(raw), u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxUint256 is the largest value the contract can store.
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ErrValueOutOfRange is returned by Set, Add and Sub for an argument the
+// contract cannot take or that lies outside cfg.MinValue and cfg.MaxValue.
+// It is returned before the node is contacted.
+var ErrValueOutOfRange = errors.New("value out of range")
+
+// checkValue rejects value if it is not a uint256 or lies outside the
+// configured bounds.
+func (c *StorageClient) checkValue(value *big.Int) error {
+	switch {
+	case value == nil:
+		return fmt.Errorf("%w: value is nil", ErrValueOutOfRange)
+	case value.Sign() < 0:
+		return fmt.Errorf("%w: %s is negative, and the contract stores unsigned integers", ErrValueOutOfRange, value)
+	case value.Cmp(maxUint256) > 0:
+		return fmt.Errorf("%w: %s does not fit in the contract's uint256", ErrValueOutOfRange, value)
+	case c.cfg.MinValue != nil && value.Cmp(c.cfg.MinValue) < 0:
+		return fmt.Errorf("%w: %s is below the minimum of %s", ErrValueOutOfRange, value, c.cfg.MinValue)
+	case c.cfg.MaxValue != nil && value.Cmp(c.cfg.MaxValue) > 0:
+		return fmt.Errorf("%w: %s is above the maximum of %s", ErrValueOutOfRange, value, c.cfg.MaxValue)
+	}
+	return nil
+}
+
+// ParseValue parses a decimal (or 0x-prefixed hex) string into a value
+// the contract accepts.  A negative number, or one too large for a
+// uint256, fails with ErrValueOutOfRange.
+func ParseValue(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty value")
+	}
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %q", s)
+	}
+	if v.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s is negative", ErrValueOutOfRange, v)
+	}
+	if v.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("%w: %s does not fit in uint256", ErrValueOutOfRange, v)
+	}
+	return v, nil
+}
+
+type txValueKey struct{}
+
+// WithTxValue returns a context under which transactions the client sends
+// carry wei of native currency to the contract, for calls to payable
+// methods.  The amount must not be negative; it is included in the
+// balance check made before sending.
+func WithTxValue(ctx context.Context, wei *big.Int) context.Context {
+	return context.WithValue(ctx, txValueKey{}, wei)
+}
+
+// txValue returns the amount attached to ctx by WithTxValue, or zero.
+func txValue(ctx context.Context) (*big.Int, error) {
+	wei, _ := ctx.Value(txValueKey{}).(*big.Int)
+	if wei == nil {
+		return new(big.Int), nil
+	}
+	if wei.Sign() < 0 {
+		return nil, fmt.Errorf("transaction value %s is negative", wei)
+	}
+	return new(big.Int).Set(wei), nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/contract/storage"
+)
+
+func FuzzParseValue(f *testing.F) {
+	for _, seed := range []string{
+		"0", "42", " 7\n", "0x2a", "0X2A", "0b101", "0o17", "1_000",
+		"-1", "-0x1", "", "abc", "1e3", "0x",
+		maxUint256.String(),
+		"0x" + strings.Repeat("f", 64),
+		new(big.Int).Add(maxUint256, big.NewInt(1)).String(),
+	} {
+		f.Add(seed)
+	}
+	contractABI, err := storage.StorageMetaData.GetAbi()
+	if err != nil {
+		f.Fatal(err)
+	}
+	set := contractABI.Methods["set"]
+	var c StorageClient
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseValue(s)
+
+		// Whatever parses as an integer out of range must be rejected as such.
+		if n, ok := new(big.Int).SetString(strings.TrimSpace(s), 0); ok && (n.Sign() < 0 || n.Cmp(maxUint256) > 0) {
+			if !errors.Is(err, ErrValueOutOfRange) {
+				t.Fatalf("ParseValue(%q) = %v, %v, want ErrValueOutOfRange", s, v, err)
+			}
+		}
+		if err != nil {
+			return
+		}
+
+		if err := c.checkValue(v); err != nil {
+			t.Fatalf("ParseValue(%q) = %s, which checkValue rejects: %v", s, v, err)
+		}
+		data, err := contractABI.Pack("set", v)
+		if err != nil {
+			t.Fatalf("packing set(%s): %v", v, err)
+		}
+		args, err := set.Inputs.Unpack(data[4:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := args[0].(*big.Int); got.Cmp(v) != 0 {
+			t.Fatalf("set(%s) packed as %s", v, got)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSubscriptions is returned by WatchValueChanged when the client has
+// no connection able to carry subscriptions.
+var ErrNoSubscriptions = errors.New("node connection does not support subscriptions")
+
+// isHTTP reports whether url selects the http transport, which cannot
+// carry subscriptions.
+func isHTTP(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// isWebsocket reports whether url selects the websocket transport.
+func isWebsocket(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// dialSubscriptions opens the separate subscription connection to
+// cfg.WSURL, if one is configured.
+func (c *StorageClient) dialSubscriptions(ctx context.Context) error {
+	if c.cfg.WSURL == "" {
+		return nil
+	}
+	if !isWebsocket(c.cfg.WSURL) {
+		return fmt.Errorf("ws url %s must use the ws:// or wss:// scheme", redactURL(c.cfg.WSURL))
+	}
+	ws, conn, err := dialSupervised(ctx, c.cfg, c.cfg.WSURL)
+	if err != nil {
+		return err
+	}
+	c.ws = ws
+	c.conns = append(c.conns, conn)
+	return nil
+}
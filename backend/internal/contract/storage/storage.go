@@ -31,7 +31,7 @@ var (
 
 // StorageMetaData contains all meta data concerning the Storage contract.
 var StorageMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"initVal\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"x\",\"type\":\"uint256\"}],\"name\":\"add\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"get\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"x\",\"type\":\"uint256\"}],\"name\":\"set\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"initVal\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"setter\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"oldValue\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newValue\",\"type\":\"uint256\"}],\"name\":\"ValueChanged\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"x\",\"type\":\"uint256\"}],\"name\":\"add\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"get\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"x\",\"type\":\"uint256\"}],\"name\":\"set\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"x\",\"type\":\"uint256\"}],\"name\":\"sub\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
 }
 
 // StorageABI is the input ABI used to generate the binding from.
@@ -252,3 +252,170 @@ func (_Storage *StorageSession) Set(x *big.Int) (*types.Transaction, error) {
 func (_Storage *StorageTransactorSession) Set(x *big.Int) (*types.Transaction, error) {
 	return _Storage.Contract.Set(&_Storage.TransactOpts, x)
 }
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 x) returns(uint256)
+func (_Storage *StorageTransactor) Sub(opts *bind.TransactOpts, x *big.Int) (*types.Transaction, error) {
+	return _Storage.contract.Transact(opts, "sub", x)
+}
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 x) returns(uint256)
+func (_Storage *StorageSession) Sub(x *big.Int) (*types.Transaction, error) {
+	return _Storage.Contract.Sub(&_Storage.TransactOpts, x)
+}
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 x) returns(uint256)
+func (_Storage *StorageTransactorSession) Sub(x *big.Int) (*types.Transaction, error) {
+	return _Storage.Contract.Sub(&_Storage.TransactOpts, x)
+}
+
+// StorageValueChangedIterator is returned from FilterValueChanged and is used to iterate over the raw logs and unpacked data for ValueChanged events raised by the Storage contract.
+type StorageValueChangedIterator struct {
+	Event *StorageValueChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log          // Log channel receiving the found contract events
+	sub  jumbochain.Subscription // Subscription for errors, completion and termination
+	done bool                    // Whether the subscription completed delivering logs
+	fail error                   // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *StorageValueChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(StorageValueChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(StorageValueChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *StorageValueChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *StorageValueChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// StorageValueChanged represents a ValueChanged event raised by the Storage contract.
+type StorageValueChanged struct {
+	Setter   common.Address
+	OldValue *big.Int
+	NewValue *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterValueChanged is a free log retrieval operation binding the contract event 0xe435f0fbe584e62b62f48f4016a57ef6c95e4c79f5babbe6ad3bb64f3281d261.
+//
+// Solidity: event ValueChanged(address indexed setter, uint256 oldValue, uint256 newValue)
+func (_Storage *StorageFilterer) FilterValueChanged(opts *bind.FilterOpts, setter []common.Address) (*StorageValueChangedIterator, error) {
+
+	var setterRule []interface{}
+	for _, setterItem := range setter {
+		setterRule = append(setterRule, setterItem)
+	}
+
+	logs, sub, err := _Storage.contract.FilterLogs(opts, "ValueChanged", setterRule)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageValueChangedIterator{contract: _Storage.contract, event: "ValueChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchValueChanged is a free log subscription operation binding the contract event 0xe435f0fbe584e62b62f48f4016a57ef6c95e4c79f5babbe6ad3bb64f3281d261.
+//
+// Solidity: event ValueChanged(address indexed setter, uint256 oldValue, uint256 newValue)
+func (_Storage *StorageFilterer) WatchValueChanged(opts *bind.WatchOpts, sink chan<- *StorageValueChanged, setter []common.Address) (event.Subscription, error) {
+
+	var setterRule []interface{}
+	for _, setterItem := range setter {
+		setterRule = append(setterRule, setterItem)
+	}
+
+	logs, sub, err := _Storage.contract.WatchLogs(opts, "ValueChanged", setterRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(StorageValueChanged)
+				if err := _Storage.contract.UnpackLog(event, "ValueChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseValueChanged is a log parse operation binding the contract event 0xe435f0fbe584e62b62f48f4016a57ef6c95e4c79f5babbe6ad3bb64f3281d261.
+//
+// Solidity: event ValueChanged(address indexed setter, uint256 oldValue, uint256 newValue)
+func (_Storage *StorageFilterer) ParseValueChanged(log types.Log) (*StorageValueChanged, error) {
+	event := new(StorageValueChanged)
+	if err := _Storage.contract.UnpackLog(event, "ValueChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
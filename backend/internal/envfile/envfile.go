@@ -0,0 +1,59 @@
+// Package envfile loads dotenv files into the process environment.
+package envfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// DefaultPath is the file loaded when none is named.
+const DefaultPath = ".env"
+
+// Paths returns the dotenv files to load: those in list, a comma-separated
+// list such as the value of an -env-file flag, or else those in ENV_FILE,
+// or else DefaultPath.
+func Paths(list string) []string {
+	if list == "" {
+		list = os.Getenv("ENV_FILE")
+	}
+	var paths []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return []string{DefaultPath}
+	}
+	return paths
+}
+
+// Load loads each of paths in order.  Variables already in the environment
+// are never overridden, so the process environment takes precedence over
+// every file and earlier files over later ones.
+//
+// A missing file is not an error, since real deployments usually set their
+// variables directly: it is logged as a warning, or at debug level for
+// DefaultPath.  A file that exists but cannot be parsed is an error.
+func Load(logger *slog.Logger, paths []string) error {
+	for _, path := range paths {
+		err := godotenv.Load(path)
+		switch {
+		case err == nil:
+			logger.Debug("loaded env file", "path", path)
+		case errors.Is(err, fs.ErrNotExist) && path == DefaultPath:
+			logger.Debug("no env file", "path", path)
+		case errors.Is(err, fs.ErrNotExist):
+			logger.Warn("env file not found", "path", path)
+		default:
+			return fmt.Errorf("loading env file %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package grpcapi exposes a StorageClient over gRPC, as the counterpart of
+// the HTTP API in internal/httpapi.  The service is defined in
+// storage.proto; the code in storagepb is generated from it by go
+// generate, which needs protoc with the protoc-gen-go and
+// protoc-gen-go-grpc plugins.
+package grpcapi
+
+//go:generate protoc --go_out=storagepb --go_opt=paths=source_relative --go-grpc_out=storagepb --go-grpc_opt=paths=source_relative storage.proto
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/digidny/simple-storage-dapp/backend/internal/grpcapi/storagepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the storage contract over gRPC.
+type Server struct {
+	storagepb.UnimplementedStorageServer
+
+	client *client.StorageClient
+}
+
+// NewServer returns a Server backed by c.
+func NewServer(c *client.StorageClient) *Server {
+	return &Server{client: c}
+}
+
+// Register registers the Storage service on gs, along with the reflection
+// service so the API can be explored with grpcurl.
+func (s *Server) Register(gs *grpc.Server) {
+	storagepb.RegisterStorageServer(gs, s)
+	reflection.Register(gs)
+}
+
+// GetValue implements storagepb.StorageServer.
+func (s *Server) GetValue(ctx context.Context, req *storagepb.GetValueRequest) (*storagepb.GetValueResponse, error) {
+	get := s.client.Get
+	if req.GetPending() {
+		get = s.client.GetPending
+	}
+	value, err := get(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &storagepb.GetValueResponse{Value: value.String()}, nil
+}
+
+// SetValue implements storagepb.StorageServer.
+func (s *Server) SetValue(ctx context.Context, req *storagepb.SetValueRequest) (*storagepb.TxResponse, error) {
+	return s.transact(ctx, req.GetValue(), s.client.Set)
+}
+
+// AddValue implements storagepb.StorageServer.
+func (s *Server) AddValue(ctx context.Context, req *storagepb.AddValueRequest) (*storagepb.TxResponse, error) {
+	return s.transact(ctx, req.GetValue(), s.client.Add)
+}
+
+// idempotencyKeyMD is the metadata key carrying an idempotency key, the
+// counterpart of the HTTP API's Idempotency-Key header.
+const idempotencyKeyMD = "idempotency-key"
+
+// transact parses value and submits it with send.  A call carrying an
+// idempotency-key in its metadata is sent at most once per key; repeating
+// it returns the original result.
+func (s *Server) transact(ctx context.Context, value string, send func(context.Context, *big.Int) (*client.TxResult, error)) (*storagepb.TxResponse, error) {
+	v, err := client.ParseValue(value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if keys := metadata.ValueFromIncomingContext(ctx, idempotencyKeyMD); len(keys) > 0 && keys[0] != "" {
+		ctx = client.WithIdempotencyKey(ctx, keys[0])
+	}
+	result, err := send(ctx, v)
+	if errors.Is(err, client.ErrMiningTimeout) {
+		// Not a failure: the transaction may still be mined.
+		return &storagepb.TxResponse{TxHash: result.TxHash.Hex(), Pending: true}, nil
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return txResponse(result), nil
+}
+
+// txResponse converts a successful result.
+func txResponse(result *client.TxResult) *storagepb.TxResponse {
+	resp := &storagepb.TxResponse{
+		Skipped: result.Skipped,
+		Pending: result.Pending,
+	}
+	if result.NewValue != nil {
+		resp.NewValue = result.NewValue.String()
+	}
+	if result.Skipped || result.DryRun {
+		return resp
+	}
+	resp.TxHash = result.TxHash.Hex()
+	if !result.Pending {
+		resp.BlockNumber = result.BlockNumber
+		resp.GasUsed = result.GasUsed
+		resp.Succeeded = result.Succeeded()
+	}
+	return resp
+}
+
+// WatchValue implements storagepb.StorageServer.  It streams until the
+// caller cancels, or fails with FailedPrecondition if the server's node
+// connection cannot carry subscriptions.
+func (s *Server) WatchValue(req *storagepb.WatchValueRequest, stream storagepb.Storage_WatchValueServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	events := make(chan *client.ValueChanged)
+	errc := make(chan error, 1)
+	go func() { errc <- s.client.WatchValueChanged(ctx, events) }()
+	for {
+		select {
+		case err := <-errc:
+			return statusError(err)
+		case ev := <-events:
+			if err := stream.Send(valueChanged(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func valueChanged(ev *client.ValueChanged) *storagepb.ValueChanged {
+	return &storagepb.ValueChanged{
+		OldValue:    ev.OldValue.String(),
+		NewValue:    ev.NewValue.String(),
+		Setter:      ev.Setter.Hex(),
+		BlockNumber: ev.BlockNumber,
+		BlockHash:   ev.BlockHash.Hex(),
+		TxHash:      ev.TxHash.Hex(),
+		LogIndex:    uint32(ev.LogIndex),
+		Removed:     ev.Removed,
+	}
+}
+
+// statusError converts err to a gRPC status with the code it calls for.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(code(err), err.Error())
+}
+
+// code returns the gRPC status code for err, following the HTTP API's
+// choice of status codes.
+func code(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, client.ErrTimeout):
+		return codes.DeadlineExceeded
+	case errors.Is(err, client.ErrValueOutOfRange), errors.Is(err, client.ErrInvalidArgument):
+		return codes.InvalidArgument
+	case errors.Is(err, client.ErrReadOnly), errors.Is(err, client.ErrNotAuthorized):
+		return codes.PermissionDenied
+	case errors.Is(err, client.ErrCircuitOpen):
+		return codes.Unavailable
+	case errors.Is(err, client.ErrIdempotencyConflict),
+		errors.Is(err, client.ErrReverted),
+		errors.Is(err, client.ErrInsufficientFunds),
+		errors.Is(err, client.ErrNoSubscriptions),
+		errors.Is(err, client.ErrContractNotDeployed):
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
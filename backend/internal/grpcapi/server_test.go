@@ -0,0 +1,85 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{fmt.Errorf("fetching balance: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{client.ErrTimeout, codes.DeadlineExceeded},
+		{fmt.Errorf("%w: -1", client.ErrValueOutOfRange), codes.InvalidArgument},
+		{client.ErrReadOnly, codes.PermissionDenied},
+		{client.ErrCircuitOpen, codes.Unavailable},
+		{&client.RevertError{Reason: "SimpleStorage: underflow"}, codes.FailedPrecondition},
+		{client.ErrIdempotencyConflict, codes.FailedPrecondition},
+		{client.ErrNoSubscriptions, codes.FailedPrecondition},
+		{errors.New("connection refused"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := code(tt.err); got != tt.want {
+			t.Errorf("code(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestStatusErrorKeepsMessage(t *testing.T) {
+	err := statusError(fmt.Errorf("sending set transaction: %w", client.ErrReadOnly))
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("statusError returned %T, want a status", err)
+	}
+	if st.Code() != codes.PermissionDenied || st.Message() != "sending set transaction: "+client.ErrReadOnly.Error() {
+		t.Errorf("status = %s %q", st.Code(), st.Message())
+	}
+}
+
+func TestReflectionListsService(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	NewServer(nil).Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		if svc.GetName() == "simplestorage.v1.Storage" {
+			return
+		}
+	}
+	t.Errorf("reflection lists %v, want simplestorage.v1.Storage", resp.GetListServicesResponse().GetService())
+}
@@ -0,0 +1,262 @@
+// Service definition for the gRPC counterpart of the HTTP API in
+// internal/httpapi.  Values are decimal strings, as in the HTTP API, so
+// uint256 values survive the trip intact.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: storage.proto
+
+package storagepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Storage_GetValue_FullMethodName   = "/simplestorage.v1.Storage/GetValue"
+	Storage_SetValue_FullMethodName   = "/simplestorage.v1.Storage/SetValue"
+	Storage_AddValue_FullMethodName   = "/simplestorage.v1.Storage/AddValue"
+	Storage_WatchValue_FullMethodName = "/simplestorage.v1.Storage/WatchValue"
+)
+
+// StorageClient is the client API for Storage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StorageClient interface {
+	// GetValue returns the value currently stored in the contract.
+	GetValue(ctx context.Context, in *GetValueRequest, opts ...grpc.CallOption) (*GetValueResponse, error)
+	// SetValue stores value and waits for the transaction to be mined.
+	SetValue(ctx context.Context, in *SetValueRequest, opts ...grpc.CallOption) (*TxResponse, error)
+	// AddValue adds value to the stored value and waits for the
+	// transaction to be mined.
+	AddValue(ctx context.Context, in *AddValueRequest, opts ...grpc.CallOption) (*TxResponse, error)
+	// WatchValue streams every ValueChanged event until the client cancels.
+	WatchValue(ctx context.Context, in *WatchValueRequest, opts ...grpc.CallOption) (Storage_WatchValueClient, error)
+}
+
+type storageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageClient(cc grpc.ClientConnInterface) StorageClient {
+	return &storageClient{cc}
+}
+
+func (c *storageClient) GetValue(ctx context.Context, in *GetValueRequest, opts ...grpc.CallOption) (*GetValueResponse, error) {
+	out := new(GetValueResponse)
+	err := c.cc.Invoke(ctx, Storage_GetValue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetValue(ctx context.Context, in *SetValueRequest, opts ...grpc.CallOption) (*TxResponse, error) {
+	out := new(TxResponse)
+	err := c.cc.Invoke(ctx, Storage_SetValue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddValue(ctx context.Context, in *AddValueRequest, opts ...grpc.CallOption) (*TxResponse, error) {
+	out := new(TxResponse)
+	err := c.cc.Invoke(ctx, Storage_AddValue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) WatchValue(ctx context.Context, in *WatchValueRequest, opts ...grpc.CallOption) (Storage_WatchValueClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[0], Storage_WatchValue_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageWatchValueClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_WatchValueClient interface {
+	Recv() (*ValueChanged, error)
+	grpc.ClientStream
+}
+
+type storageWatchValueClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageWatchValueClient) Recv() (*ValueChanged, error) {
+	m := new(ValueChanged)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StorageServer is the server API for Storage service.
+// All implementations must embed UnimplementedStorageServer
+// for forward compatibility
+type StorageServer interface {
+	// GetValue returns the value currently stored in the contract.
+	GetValue(context.Context, *GetValueRequest) (*GetValueResponse, error)
+	// SetValue stores value and waits for the transaction to be mined.
+	SetValue(context.Context, *SetValueRequest) (*TxResponse, error)
+	// AddValue adds value to the stored value and waits for the
+	// transaction to be mined.
+	AddValue(context.Context, *AddValueRequest) (*TxResponse, error)
+	// WatchValue streams every ValueChanged event until the client cancels.
+	WatchValue(*WatchValueRequest, Storage_WatchValueServer) error
+	mustEmbedUnimplementedStorageServer()
+}
+
+// UnimplementedStorageServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageServer struct {
+}
+
+func (UnimplementedStorageServer) GetValue(context.Context, *GetValueRequest) (*GetValueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValue not implemented")
+}
+func (UnimplementedStorageServer) SetValue(context.Context, *SetValueRequest) (*TxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetValue not implemented")
+}
+func (UnimplementedStorageServer) AddValue(context.Context, *AddValueRequest) (*TxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddValue not implemented")
+}
+func (UnimplementedStorageServer) WatchValue(*WatchValueRequest, Storage_WatchValueServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchValue not implemented")
+}
+func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
+
+// UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageServer will
+// result in compilation errors.
+type UnsafeStorageServer interface {
+	mustEmbedUnimplementedStorageServer()
+}
+
+func RegisterStorageServer(s grpc.ServiceRegistrar, srv StorageServer) {
+	s.RegisterService(&Storage_ServiceDesc, srv)
+}
+
+func _Storage_GetValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetValue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetValue(ctx, req.(*GetValueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetValueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetValue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetValue(ctx, req.(*SetValueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddValue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddValueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddValue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_AddValue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddValue(ctx, req.(*AddValueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_WatchValue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchValueRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).WatchValue(m, &storageWatchValueServer{stream})
+}
+
+type Storage_WatchValueServer interface {
+	Send(*ValueChanged) error
+	grpc.ServerStream
+}
+
+type storageWatchValueServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageWatchValueServer) Send(m *ValueChanged) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Storage_ServiceDesc is the grpc.ServiceDesc for Storage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Storage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simplestorage.v1.Storage",
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetValue",
+			Handler:    _Storage_GetValue_Handler,
+		},
+		{
+			MethodName: "SetValue",
+			Handler:    _Storage_SetValue_Handler,
+		},
+		{
+			MethodName: "AddValue",
+			Handler:    _Storage_AddValue_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchValue",
+			Handler:       _Storage_WatchValue_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "storage.proto",
+}
@@ -0,0 +1,153 @@
+// Package history records the transactions a StorageClient submits so they
+// can be audited later.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record statuses.
+const (
+	StatusSuccess  = "success"
+	StatusReverted = "reverted"
+	StatusError    = "error"   // the outcome could not be determined
+	StatusPending  = "pending" // submitted without waiting for the receipt
+)
+
+// Record describes one submitted transaction once it has resolved, or once
+// it has been submitted when the client does not wait for it.
+type Record struct {
+	Time        time.Time `json:"time"`
+	Method      string    `json:"method"`
+	Args        []string  `json:"args,omitempty"`
+	TxHash      string    `json:"txHash"`
+	BlockNumber uint64    `json:"blockNumber,omitempty"`
+	GasUsed     uint64    `json:"gasUsed,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+
+	// IdempotencyKey is the key the transaction was submitted under, if
+	// any.
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// Store persists Records.  Implementations must be safe for concurrent use.
+type Store interface {
+	Append(rec Record) error
+}
+
+// Finder is implemented by Stores that can be queried.
+type Finder interface {
+	Find(f Filter) ([]Record, error)
+}
+
+// FileStore appends Records to a file as JSON lines.  The file is opened
+// for each write, so it can be rotated or removed while the process runs.
+type FileStore struct {
+	path string
+
+	mu sync.Mutex
+}
+
+// NewFileStore returns a FileStore writing to path.  The file is created on
+// the first Append.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Append implements Store.
+func (s *FileStore) Append(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening history file: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("writing history file: %w", err)
+	}
+	return f.Close()
+}
+
+// Find implements Finder.  A file not yet created holds no Records.
+func (s *FileStore) Find(f Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recs, err := ReadFile(s.path, f)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return recs, err
+}
+
+// Filter selects Records.  Zero fields match everything.
+type Filter struct {
+	Method         string
+	Status         string
+	Since          time.Time
+	IdempotencyKey string
+}
+
+// Match reports whether rec passes f.
+func (f Filter) Match(rec Record) bool {
+	if f.Method != "" && rec.Method != f.Method {
+		return false
+	}
+	if f.Status != "" && rec.Status != f.Status {
+		return false
+	}
+	if !f.Since.IsZero() && rec.Time.Before(f.Since) {
+		return false
+	}
+	if f.IdempotencyKey != "" && rec.IdempotencyKey != f.IdempotencyKey {
+		return false
+	}
+	return true
+}
+
+// Read decodes the JSON lines in r and returns the Records matching f, in
+// file order.
+func Read(r io.Reader, f Filter) ([]Record, error) {
+	var recs []Record
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if f.Match(rec) {
+			recs = append(recs, rec)
+		}
+	}
+	return recs, sc.Err()
+}
+
+// ReadFile is Read on the file at path.
+func ReadFile(path string, f Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file, f)
+}
@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+)
+
+// healthCacheTTL is how long a probe result is reused, so frequent probes
+// do not each reach the node.
+const healthCacheTTL = 5 * time.Second
+
+// cachedCheck runs check at most once per healthCacheTTL and remembers its
+// result in between.
+type cachedCheck struct {
+	check func(ctx context.Context) error
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *cachedCheck) run(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < healthCacheTTL {
+		return c.err
+	}
+	c.err = c.check(ctx)
+	c.checked = time.Now()
+	return c.err
+}
+
+type healthResponse struct {
+	Status  string `json:"status"`
+	Breaker string `json:"breaker"`
+}
+
+// status builds a health response, reporting the circuit breaker too.
+func (s *Server) status(status string) healthResponse {
+	return healthResponse{Status: status, Breaker: s.client.BreakerState().String()}
+}
+
+// degraded reports whether the client is re-dialing a lost connection or
+// its circuit breaker is open, so requests to the node would fail.
+func (s *Server) degraded() bool {
+	return s.client.ConnState() == client.ConnReconnecting || s.client.BreakerState() == client.BreakerOpen
+}
+
+// handleHealthz reports whether the node is reachable.  While the client
+// is degraded it answers 200 with status "degraded": the process is
+// recovering on its own and restarting it would not help.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if s.degraded() {
+		writeJSON(w, http.StatusOK, s.status("degraded"))
+		return
+	}
+	if err := s.live.run(r.Context()); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.status("ok"))
+}
+
+// handleReadyz reports whether the node is reachable and the contract is
+// deployed at the configured address.  It answers 503 with status
+// "degraded" while the client is degraded.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.degraded() {
+		writeJSON(w, http.StatusServiceUnavailable, s.status("degraded"))
+		return
+	}
+	if err := s.live.run(r.Context()); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := s.ready.run(r.Context()); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.status("ok"))
+}
@@ -0,0 +1,276 @@
+// Package httpapi exposes a StorageClient over a small JSON HTTP API.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/digidny/simple-storage-dapp/backend/internal/logging"
+	"github.com/digidny/simple-storage-dapp/backend/internal/metrics"
+)
+
+// Server serves the storage contract over HTTP.
+//
+//	GET  /value  returns the stored value, and with ?initialized=true
+//	             whether one was ever stored
+//	POST /value  sets the value, body {"value": "150"}
+//	POST /add    adds to the value, body {"value": "10"}
+//	POST /relay  sends a set, add or sub signed by a user, if RELAY is set
+//	GET  /relay/nonce/{address}  the nonce the user's next relay request needs
+//	GET  /metrics  Prometheus metrics, if enabled
+//	GET  /healthz  200 if the node is reachable or the client is recovering
+//	GET  /readyz   200 if the node is reachable and the contract deployed
+type Server struct {
+	client *client.StorageClient
+	mux    *http.ServeMux
+
+	live  *cachedCheck
+	ready *cachedCheck
+}
+
+// NewServer returns a Server backed by c.  m may be nil to disable the
+// metrics endpoint.
+func NewServer(c *client.StorageClient, m *metrics.Metrics) *Server {
+	s := &Server{
+		client: c,
+		mux:    http.NewServeMux(),
+		live: &cachedCheck{check: func(ctx context.Context) error {
+			_, err := c.BlockNumber(ctx)
+			return err
+		}},
+		ready: &cachedCheck{check: c.CheckContract},
+	}
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+	s.mux.HandleFunc("GET /value", s.handleGet)
+	s.mux.HandleFunc("POST /value", s.handleSet)
+	s.mux.HandleFunc("POST /add", s.handleAdd)
+	s.mux.HandleFunc("POST /relay", s.handleRelay)
+	s.mux.HandleFunc("GET /relay/nonce/{address}", s.handleRelayNonce)
+	if m != nil {
+		s.mux.Handle("GET /metrics", m.Handler())
+	}
+	return s
+}
+
+// requestIDHeader carries the ID of a request, in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// ServeHTTP implements http.Handler.  Every request is tagged with the ID
+// in its X-Request-ID header, or a fresh one if it has none, which is
+// echoed in the response and logged with each line the client writes
+// while serving it.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(requestIDHeader)
+	if !validRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	w.Header().Set(requestIDHeader, id)
+	s.mux.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+}
+
+// validRequestID reports whether a caller's request ID is safe to log
+// and echo: at most 128 printable ASCII characters without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+type valueRequest struct {
+	Value string `json:"value"`
+}
+
+type valueResponse struct {
+	Value       string `json:"value"`
+	Initialized *bool  `json:"initialized,omitempty"`
+}
+
+type txResponse struct {
+	TxHash      string `json:"txHash,omitempty"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+	GasUsed     uint64 `json:"gasUsed,omitempty"`
+
+	DryRun       bool   `json:"dryRun,omitempty"`
+	EstimatedGas uint64 `json:"estimatedGas,omitempty"`
+	NewValue     string `json:"newValue,omitempty"`
+
+	Skipped bool `json:"skipped,omitempty"`
+	Pending bool `json:"pending,omitempty"`
+}
+
+type errorResponse struct {
+	Error  string `json:"error"`
+	TxHash string `json:"txHash,omitempty"`
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("initialized") == "true" {
+		value, written, err := s.client.GetInitialized(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, valueResponse{Value: value.String(), Initialized: &written})
+		return
+	}
+	value, err := s.client.Get(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, valueResponse{Value: value.String()})
+}
+
+func (s *Server) handleSet(w http.ResponseWriter, r *http.Request) {
+	s.handleTransact(w, r, s.client.Set)
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	s.handleTransact(w, r, s.client.Add)
+}
+
+// transactFunc is the signature shared by StorageClient.Set and Add.
+type transactFunc func(ctx context.Context, value *big.Int) (*client.TxResult, error)
+
+// handleTransact decodes a valueRequest and submits it with send.  A
+// request carrying an Idempotency-Key header is sent at most once per key;
+// repeating it returns the original result.
+func (s *Server) handleTransact(w http.ResponseWriter, r *http.Request, send transactFunc) {
+	var req valueRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	value, err := client.ParseValue(req.Value)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = client.WithIdempotencyKey(ctx, key)
+	}
+	result, err := send(ctx, value)
+	writeTxResult(w, result, err)
+}
+
+// maxBodyBytes bounds a request body, far above what any valid request
+// needs.
+const maxBodyBytes = 1 << 20
+
+// decodeBody decodes the JSON body of r into v and reports whether it
+// could.  If not, it has written a 413 for a body over maxBodyBytes or a
+// 400 for any other problem.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(v)
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request body: " + err.Error()})
+	return false
+}
+
+// handleRelay decodes a client.RelayRequest and relays it.
+func (s *Server) handleRelay(w http.ResponseWriter, r *http.Request) {
+	var req client.RelayRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	result, err := s.client.Relay(r.Context(), &req)
+	writeTxResult(w, result, err)
+}
+
+type relayNonceResponse struct {
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+}
+
+func (s *Server) handleRelayNonce(w http.ResponseWriter, r *http.Request) {
+	user, err := client.ParseAddress(r.PathValue("address"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	nonce, err := s.client.RelayNonce(user)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, relayNonceResponse{Address: user.Hex(), Nonce: strconv.FormatUint(nonce, 10)})
+}
+
+// writeTxResult writes the outcome of a transaction, or err with the
+// status code it calls for.
+func writeTxResult(w http.ResponseWriter, result *client.TxResult, err error) {
+	if errors.Is(err, client.ErrMiningTimeout) {
+		// Not a failure: the transaction may still be mined.
+		writeJSON(w, http.StatusAccepted, txResponse{TxHash: result.TxHash.Hex(), Pending: true})
+		return
+	}
+	if err != nil {
+		resp := errorResponse{Error: err.Error()}
+		if result != nil {
+			resp.TxHash = result.TxHash.Hex()
+		}
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, client.ErrValueOutOfRange), errors.Is(err, client.ErrInvalidArgument):
+			status = http.StatusBadRequest
+		case errors.Is(err, client.ErrIdempotencyConflict):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, client.ErrReadOnly), errors.Is(err, client.ErrRelayDisabled):
+			status = http.StatusForbidden
+		case errors.Is(err, client.ErrRelaySignature):
+			status = http.StatusUnauthorized
+		case errors.Is(err, client.ErrRelayNonce):
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, resp)
+		return
+	}
+	if result.Skipped {
+		writeJSON(w, http.StatusOK, txResponse{Skipped: true, NewValue: result.NewValue.String()})
+		return
+	}
+	if result.DryRun {
+		writeJSON(w, http.StatusOK, txResponse{
+			DryRun:       true,
+			EstimatedGas: result.EstimatedGas,
+			NewValue:     result.NewValue.String(),
+		})
+		return
+	}
+	if result.Pending {
+		writeJSON(w, http.StatusAccepted, txResponse{TxHash: result.TxHash.Hex(), Pending: true})
+		return
+	}
+	writeJSON(w, http.StatusOK, txResponse{
+		TxHash:      result.TxHash.Hex(),
+		BlockNumber: result.BlockNumber,
+		GasUsed:     result.GasUsed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,31 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestBodyLimit(t *testing.T) {
+	s := NewServer(nil, nil)
+	huge := `{"value": "` + strings.Repeat("1", maxBodyBytes) + `"}`
+	tests := []struct {
+		path, body string
+		want       int
+	}{
+		{"/value", huge, http.StatusRequestEntityTooLarge},
+		{"/add", huge, http.StatusRequestEntityTooLarge},
+		{"/relay", huge, http.StatusRequestEntityTooLarge},
+		{"/value", `{"value": `, http.StatusBadRequest},
+		{"/relay", `[]`, http.StatusBadRequest},
+		{"/value", `{"value": "-1"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("POST %s with a %d-byte body = %d %s, want %d", tt.path, len(tt.body), rec.Code, rec.Body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package logging builds the slog loggers used by the commands.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// New returns a logger writing to w.  format is "text" or "json" and level
+// is one of "debug", "info", "warn" or "error"; empty strings select text
+// output at info level.  Records logged with a context carrying a request
+// ID include it; see WithRequestID.
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q", level)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(requestIDHandler{slog.NewTextHandler(w, opts)}), nil
+	case "json":
+		return slog.New(requestIDHandler{slog.NewJSONHandler(w, opts)}), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+}
+
+// FromEnv returns a stderr logger configured by LOG_FORMAT and LOG_LEVEL.
+func FromEnv() (*slog.Logger, error) {
+	return New(os.Stderr, os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+}
@@ -0,0 +1,134 @@
+// Package metrics records Prometheus metrics for StorageClient operations.
+package metrics
+
+import (
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcome labels.
+const (
+	OutcomeSuccess = "success"
+	OutcomeRevert  = "revert"
+	OutcomeError   = "error"
+)
+
+const namespace = "simple_storage"
+
+// Metrics holds the collectors for one registry.  A nil *Metrics is valid
+// and records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	gasUsed  *prometheus.HistogramVec
+	gasSpent *prometheus.CounterVec
+
+	gasPrice        *prometheus.GaugeVec
+	gasPriceUpdated *prometheus.GaugeVec
+
+	poolConns *prometheus.GaugeVec
+	poolInUse *prometheus.GaugeVec
+}
+
+// New creates the collectors and registers them with reg.  Tests can pass
+// a fresh registry and gather from it to assert on recorded values.
+func New(reg *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: reg,
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "calls_total",
+			Help:      "Contract calls and transactions by method and outcome.",
+		}, []string{"method", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "call_duration_seconds",
+			Help:      "Time taken by contract calls and transactions, including waiting for receipts.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+		}, []string{"method", "outcome"}),
+		gasUsed: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "gas_used",
+			Help:      "Gas used by mined transactions.",
+			Buckets:   prometheus.ExponentialBuckets(21000, 1.5, 10),
+		}, []string{"method"}),
+		gasSpent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "gas_used_total",
+			Help:      "Gas used by mined transactions, summed, by method.",
+		}, []string{"method"}),
+		gasPrice: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "gas_price_wei",
+			Help:      "Cached gas price and tip cap suggested by the node, by kind.",
+		}, []string{"kind"}),
+		gasPriceUpdated: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "gas_price_updated_timestamp_seconds",
+			Help:      "Unix time the cached gas price of each kind was fetched; subtract from time() for its age.",
+		}, []string{"kind"}),
+		poolConns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rpc_pool_connections",
+			Help:      "Connections open in the RPC connection pool, by endpoint host.",
+		}, []string{"endpoint"}),
+		poolInUse: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rpc_pool_connections_in_use",
+			Help:      "Pooled RPC connections with a request in flight, by endpoint host.",
+		}, []string{"endpoint"}),
+	}
+	reg.MustRegister(m.calls, m.duration, m.gasUsed, m.gasSpent, m.gasPrice, m.gasPriceUpdated, m.poolConns, m.poolInUse)
+	return m
+}
+
+// Observe records one call to method that took d and ended with outcome.
+func (m *Metrics) Observe(method, outcome string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.calls.WithLabelValues(method, outcome).Inc()
+	m.duration.WithLabelValues(method, outcome).Observe(d.Seconds())
+}
+
+// ObserveGas records the gas used by a mined transaction, both in the
+// per-method histogram and in the running total.
+func (m *Metrics) ObserveGas(method string, gas uint64) {
+	if m == nil {
+		return
+	}
+	m.gasUsed.WithLabelValues(method).Observe(float64(gas))
+	m.gasSpent.WithLabelValues(method).Add(float64(gas))
+}
+
+// ObserveGasPrice records a freshly cached gas price of kind, such as
+// "price" or "tip", fetched at.
+func (m *Metrics) ObserveGasPrice(kind string, wei *big.Int, at time.Time) {
+	if m == nil {
+		return
+	}
+	f, _ := new(big.Float).SetInt(wei).Float64()
+	m.gasPrice.WithLabelValues(kind).Set(f)
+	m.gasPriceUpdated.WithLabelValues(kind).Set(float64(at.UnixNano()) / 1e9)
+}
+
+// ObservePool records the size of the connection pool to endpoint and how
+// many of its connections are in use.
+func (m *Metrics) ObservePool(endpoint string, size, inUse int) {
+	if m == nil {
+		return
+	}
+	m.poolConns.WithLabelValues(endpoint).Set(float64(size))
+	m.poolInUse.WithLabelValues(endpoint).Set(float64(inUse))
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -2,187 +2,1042 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/digidny/simple-storage-dapp/backend/internal/storage"
-	"github.com/joho/godotenv"
-	"github.com/jumbochain/jumbochain-go/accounts/abi/bind"
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/digidny/simple-storage-dapp/backend/internal/envfile"
+	"github.com/digidny/simple-storage-dapp/backend/internal/history"
+	"github.com/digidny/simple-storage-dapp/backend/internal/logging"
 	"github.com/jumbochain/jumbochain-go/common"
-	"github.com/jumbochain/jumbochain-go/core/types"
-	"github.com/jumbochain/jumbochain-go/crypto"
-	"github.com/jumbochain/jumbochain-go/jumboclient"
 )
 
 // Ensure this matches the contract ABI.  Use `abigen` to generate.
 //go:generate abigen --abi=../build/SimpleStorage.abi --pkg=storage --out=./internal/contract/storage/storage.go
 
-// Contract binding package.
-type Storage struct {
-	address common.Address
-	abi     string
+func main() {
+	logger, err := logging.FromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Cancel the root context on Ctrl-C or SIGTERM so in-flight waits
+	// return promptly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = logging.WithRequestID(ctx, logging.NewRequestID())
+
+	out := &output{}
+	if err := run(ctx, logger, out, os.Args[1:]); err != nil {
+		stop()
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			if out.json {
+				out.error(logger, err)
+			}
+			os.Exit(2)
+		}
+		out.error(logger, err)
+		os.Exit(1)
+	}
 }
 
-func main() {
-	// Load environment variables from .env file.
-	err := godotenv.Load()
+// run executes the command selected by args, writing its results to out.
+func run(ctx context.Context, logger *slog.Logger, out *output, args []string) error {
+	fs := flag.NewFlagSet("simple-storage", flag.ContinueOnError)
+	envFiles := fs.String("env-file", "", "comma-separated dotenv files to load, earlier ones taking precedence (default ENV_FILE or .env)")
+	network := fs.String("network", "", "network to use from the networks file (default NETWORK)")
+	networksPath := fs.String("networks", "", "path to the networks file (default NETWORKS_FILE or networks.json)")
+	fs.BoolVar(&out.json, "json", false, "print results and errors as JSON")
+	configFile := fs.String("config", "", "JSON file of settings, below the environment in precedence (default CONFIG_FILE)")
+	printConfig := fs.Bool("print-config", false, "print the resolved settings and where each came from, then exit")
+	settings := make(client.SettingFlags)
+	fs.Var(settings, "set", "set a setting by its environment variable name, as `NAME=value`, overriding every other source; repeatable")
+	account := fs.String("account", "", "account to sign with: an index or address among the accounts of MNEMONIC or a KEYSTORE_PATH directory (default ACCOUNT_INDEX or SIGNER_ADDRESS)")
+	gasPrice := fs.String("gas-price", "", "gas price in gwei, overriding the node's suggestion (default GAS_PRICE)")
+	gasTier := fs.String("gas-tier", "", "fee tier to take from GAS_ORACLE_URL: slow, standard or fast (default GAS_TIER)")
+	skipBalanceCheck := fs.Bool("skip-balance-check", false, "do not check the sender can afford a transaction before sending it")
+	gasFallback := fs.Bool("gas-estimate-fallback", false, "send with DEFAULT_GAS_LIMIT when gas estimation fails (default GAS_ESTIMATE_FALLBACK)")
+	readOnly := fs.Bool("read-only", false, "load no signing key and refuse to send transactions (default READ_ONLY)")
+	abiFile := fs.String("abi", "", "contract ABI JSON for call and transact, instead of the generated one (default ABI_FILE)")
+	fs.Usage = printUsage(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) == 0 && !*printConfig {
+		fs.Usage()
+		return errUsage
+	}
+
+	// Load the dotenv files before anything reads the environment.
+	if err := envfile.Load(logger, envfile.Paths(*envFiles)); err != nil {
+		return err
+	}
+	if *network == "" {
+		*network = os.Getenv("NETWORK")
+	}
+	if *networksPath == "" {
+		*networksPath = envOr("NETWORKS_FILE", "networks.json")
+	}
+	if *configFile == "" {
+		*configFile = os.Getenv("CONFIG_FILE")
+	}
+
+	// The dedicated flags are shorthands for -set.
+	switch {
+	case *account == "":
+	case common.IsHexAddress(*account):
+		settings["SIGNER_ADDRESS"] = *account
+	default:
+		if _, err := strconv.ParseUint(*account, 10, 32); err != nil {
+			return fmt.Errorf("-account %q is neither an account index nor an address", *account)
+		}
+		settings["ACCOUNT_INDEX"] = *account
+	}
+	if *gasPrice != "" {
+		settings["GAS_PRICE"] = *gasPrice
+	}
+	if *gasTier != "" {
+		settings["GAS_TIER"] = *gasTier
+	}
+	if *skipBalanceCheck {
+		settings["SKIP_BALANCE_CHECK"] = "true"
+	}
+	if *gasFallback {
+		settings["GAS_ESTIMATE_FALLBACK"] = "true"
+	}
+	if *readOnly {
+		settings["READ_ONLY"] = "true"
+	}
+	if *abiFile != "" {
+		settings["ABI_FILE"] = *abiFile
+	}
+
+	// Every setting comes from the first of the flags, the environment,
+	// the selected network entry and the config file to set it.
+	cfg, resolved, err := client.Layers{
+		Flags:        settings,
+		NetworksPath: *networksPath,
+		Network:      *network,
+		FilePath:     *configFile,
+	}.Load()
 	if err != nil {
-		log.Fatal("Error loading .env file:", err)
+		return err
+	}
+	cfg.Logger = logger
+	if *printConfig {
+		return runPrintConfig(out, resolved)
 	}
 
-	// Connect to the Ethereum client.  Use the URL from the environment.
-	rpcURL := os.Getenv("RPC_URL") // e.g., "http://localhost:8545"
-	if rpcURL == "" {
-		log.Fatal("ETH_URL environment variable not set")
+	// Report every configuration problem up front.  Commands that do not
+	// talk to the node check only what they use.
+	switch args[0] {
+	case "get", "set", "add", "sub", "call", "transact", "estimate", "status", "watch", "react", "events", "broadcast", "relay", "export", "import", "migrate", "storage-slot", "capabilities", "demo":
+		err = cfg.Validate()
+	case "deploy":
+		err = cfg.ValidateForDeploy()
 	}
-	client, err := jumboclient.DialContext(context.Background(), rpcURL)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	defer client.Close()
 
-	// 1. Deploy the contract (or use an existing address).
-	//    For this example, we assume it's already deployed.  In a real
-	//    application, you'd use the `DeployContract` function from
-	//    the `bind` package.  We'll fetch the address from the env.
-	contractAddressStr := os.Getenv("CONTRACT_ADDRESS")
-	if contractAddressStr == "" {
-		log.Fatal("CONTRACT_ADDRESS environment variable not set")
+	switch args[0] {
+	case "get":
+		return runGet(ctx, cfg, out, args[1:])
+	case "set":
+		return runTransact(ctx, cfg, out, "set", args[1:], (*client.StorageClient).Set)
+	case "add":
+		return runTransact(ctx, cfg, out, "add", args[1:], (*client.StorageClient).Add)
+	case "sub":
+		return runTransact(ctx, cfg, out, "sub", args[1:], (*client.StorageClient).Sub)
+	case "call":
+		return runCall(ctx, cfg, out, args[1:])
+	case "transact":
+		return runGenericTransact(ctx, cfg, out, args[1:])
+	case "estimate":
+		return runEstimate(ctx, cfg, out, args[1:])
+	case "deploy":
+		return runDeploy(ctx, cfg, out, args[1:])
+	case "watch":
+		return runWatch(ctx, cfg, out, args[1:])
+	case "react":
+		return runReact(ctx, cfg, out, args[1:])
+	case "events":
+		return runEvents(ctx, cfg, out, args[1:])
+	case "sign":
+		return runSign(cfg, out, args[1:])
+	case "status":
+		return runStatus(ctx, cfg, out, args[1:])
+	case "broadcast":
+		return runBroadcast(ctx, cfg, out, args[1:])
+	case "relay":
+		return runRelay(ctx, cfg, out, args[1:])
+	case "history":
+		return runHistory(out, args[1:])
+	case "stats":
+		return runStats(out, args[1:])
+	case "export":
+		return runExport(ctx, cfg, out, args[1:])
+	case "import":
+		return runImport(ctx, cfg, out, args[1:])
+	case "migrate":
+		return runMigrate(ctx, cfg, out, args[1:])
+	case "storage-slot":
+		return runStorageSlot(ctx, cfg, out, args[1:])
+	case "new-account":
+		return runNewAccount(cfg, out, args[1:])
+	case "capabilities":
+		return runCapabilities(ctx, cfg, out)
+	case "demo":
+		return runDemo(ctx, cfg, out)
+	default:
+		fmt.Fprintf(fs.Output(), "unknown command %q\n", args[0])
+		fs.Usage()
+		return errUsage
 	}
-	contractAddress := common.HexToAddress(contractAddressStr)
-	fmt.Println("Contract Address:", contractAddress)
+}
 
-	// 2. Create an instance of the contract binding.
-	instance, err := storage.NewSimpleStorage(contractAddress, client)
+// errUsage is returned after usage has been printed for a bad command line.
+var errUsage = errors.New("invalid usage")
+
+const usage = `Usage: simple-storage [flags] <command> [args]
+       simple-storage [flags] -print-config
+
+Settings are named by their environment variables.  Each is taken from the
+first of -set NAME=value (or a dedicated flag), the environment, the
+-network entry of the networks file, and the -config file to set it.
+
+Commands:
+  get [-pending | -block n | -initialized]
+                   print the stored value
+  set [-value wei] [-idempotency-key k] [-no-wait | -timeout d] [-yes] [-simulate=false] <value>
+                   store value
+  add [-value wei] [-idempotency-key k] [-no-wait | -timeout d] [-yes] [-simulate=false] <delta>
+                   add delta to the stored value
+  sub [-value wei] [-idempotency-key k] [-no-wait | -timeout d] [-yes] [-simulate=false] <delta>
+                   subtract delta from the stored value
+  call <method> [args...]
+                   call any read-only method of the contract, see -abi
+  transact [-value wei] [-no-wait] <method> [args...]
+                   send a transaction calling any method of the contract
+  estimate [-value wei] <method> <value>
+                   print the gas and maximum cost of a set, add or sub
+  status <txhash>  report whether a transaction is pending, mined or failed
+  deploy [-init v] [-estimate-only]
+                   deploy a new contract, or print what that would cost
+  watch [-by address] [-duration d] [-max-events n]
+                   print value changes as they happen
+  react [-min v] [-max v] [-cooldown d]
+                   keep the value in range, answering changes with add or set
+  events [-from n] [-to n] [-by address]
+                   print past value changes
+  sign <method> <value>
+                   sign a set, add or sub transaction offline
+  broadcast <raw>  submit a transaction produced by sign
+  relay sign -nonce n <method> <value>
+                   sign a set, add or sub for a relayer to pay for
+  relay send <file>
+                   send a request produced by relay sign, paying its gas
+  relay nonce <address>
+                   print the nonce the user's next relay request needs
+  history          print recorded transactions
+  stats [-since d] print the gas used per method by recorded transactions
+  export <file>    write the stored value and its metadata to file
+  import [-yes] <file>
+                   restore the value recorded by export
+  migrate [-from-rpc url] [-yes] <old-address>
+                   copy the value of an old contract into this one
+  storage-slot [-int] [-block n] <slot>
+                   print a raw storage slot of the contract, the value is in slot 0
+  new-account [-keystore dir] [-print-key]
+                   generate a key and print its address
+  capabilities     report which optional node features were detected
+  demo             walk through get, set and add
+
+Flags:
+`
+
+// printUsage is the Usage function of the top-level flag set.
+func printUsage(fs *flag.FlagSet) func() {
+	return func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+}
+
+// runGet prints the stored value.
+func runGet(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	pending := fs.Bool("pending", false, "read the pending state, including unmined transactions")
+	block := fs.String("block", "latest", `block to read the value at, or "latest"`)
+	initialized := fs.Bool("initialized", false, "also report whether a value was ever stored, telling a fresh contract's 0 from a written one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *initialized && (*pending || *block != "latest") {
+		return fmt.Errorf("-initialized cannot be combined with -pending or -block")
+	}
+	var atBlock *uint64
+	if *block != "latest" {
+		n, err := strconv.ParseUint(*block, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid -block %q", *block)
+		}
+		if *pending {
+			return fmt.Errorf("-pending and -block cannot be combined")
+		}
+		atBlock = &n
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	defer storageClient.Close()
 
-	// 3. Get the initial value.
-	initialValue, err := instance.Get(nil)
+	if *initialized {
+		value, written, err := storageClient.GetInitialized(ctx)
+		if err != nil {
+			return err
+		}
+		out.print(valueOutput{Value: value.String(), Initialized: &written}, func() {
+			if written {
+				fmt.Println(value)
+			} else {
+				fmt.Println(value, "(never stored)")
+			}
+		})
+		return nil
+	}
+
+	get := storageClient.Get
+	switch {
+	case *pending:
+		get = storageClient.GetPending
+	case atBlock != nil:
+		get = func(ctx context.Context) (*big.Int, error) { return storageClient.GetAtBlock(ctx, *atBlock) }
+	}
+	value, err := get(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	fmt.Println("Initial value:", initialValue)
+	out.print(valueOutput{Value: value.String()}, func() { fmt.Println(value) })
+	return nil
+}
 
-	// 4. Set a new value.
-	auth, err := getTransactionAuthorizer(client) // Get auth for making tx
+// runTransact parses the single value argument of the set and add commands
+// and submits it with send.
+func runTransact(ctx context.Context, cfg client.Config, out *output, name string, args []string, send func(*client.StorageClient, context.Context, *big.Int) (*client.TxResult, error)) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	wei := fs.String("value", "", "native currency to send with the transaction, in wei")
+	key := fs.String("idempotency-key", "", "send at most one transaction for this key (needs TX_HISTORY_FILE to work across runs)")
+	wait := fs.Bool("wait", !cfg.NoWait, "wait for the transaction to be mined")
+	noWait := fs.Bool("no-wait", false, "print the transaction hash as soon as it is submitted, same as -wait=false")
+	yes := fs.Bool("yes", false, "send on a mainnet without asking for confirmation")
+	// Simulating first is the default at a terminal, where a revert is
+	// better caught before paying for it than after.  Scripts keep the
+	// SIMULATE setting, off unless set, and save the extra call.
+	simulate := fs.Bool("simulate", cfg.Simulate || interactive(), "simulate the transaction first and do not send it if it would revert")
+	timeout := fs.Duration("timeout", 0, "stop waiting after this long, leaving the transaction pending")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simple-storage %s [-value wei] [-idempotency-key k] [-no-wait | -timeout d] [-yes] [-simulate=false] <value>", name)
+	}
+	value, err := client.ParseValue(fs.Arg(0))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	if *wei != "" {
+		amount, err := client.ParseValue(*wei)
+		if err != nil {
+			return fmt.Errorf("parsing -value: %w", err)
+		}
+		ctx = client.WithTxValue(ctx, amount)
+	}
+	if *key != "" {
+		ctx = client.WithIdempotencyKey(ctx, *key)
+	}
+	cfg.NoWait = *noWait || !*wait
+	cfg.Simulate = *simulate
 
-	newValue := big.NewInt(150)
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
 
-	// 5. Estimate gas *before* sending the transaction.
-	gas, err := instance.EstimateGas(&bind.CallOpts{From: auth.From, Context: context.Background()}, "set", newValue)
+	if err := checkSender(ctx, storageClient, cfg, out); err != nil {
+		return err
+	}
+	if !*yes && !cfg.DryRun {
+		if err := confirmMainnet(ctx, storageClient, out, name, value); err != nil {
+			return err
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	result, err := send(storageClient, ctx, value)
+	if errors.Is(err, client.ErrMiningTimeout) {
+		printMiningTimeout(out, name, result)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	out.print(newTxOutput(result), func() { printTxResult(name, result) })
+	return nil
+}
+
+// checkSender prints the account a transaction is about to be sent from,
+// so a wrong -account is noticed before it pays, and fails if that
+// account holds nothing at all.
+func checkSender(ctx context.Context, c *client.StorageClient, cfg client.Config, out *output) error {
+	balance, err := c.Balance(ctx)
 	if err != nil {
-		log.Fatal("Error estimating gas:", err)
+		return err
 	}
-	fmt.Println("Estimated gas:", gas)
-	auth.GasLimit = gas + 20000 // add a buffer of 20000
+	if !out.json {
+		fmt.Printf("Sending from %s, balance %s ether\n", c.From().Hex(), client.FormatEther(balance))
+	}
+	if balance.Sign() == 0 && !cfg.DryRun && !cfg.SkipBalanceCheck {
+		return fmt.Errorf("%w: sender %s has no balance", client.ErrInsufficientFunds, c.From().Hex())
+	}
+	return nil
+}
 
-	tx, err := instance.Set(auth, newValue)
+// errConfirmationRequired is returned when a transaction on a mainnet
+// needs -yes because there is no terminal to ask on.
+var errConfirmationRequired = errors.New("refusing to send on a mainnet without confirmation, pass -yes")
+
+// confirmMainnet asks before method is sent with value if the client is
+// on a known mainnet, showing the contract and the most the transaction
+// can cost.  In JSON mode or without a terminal there is nobody to ask,
+// so it fails with errConfirmationRequired instead.
+func confirmMainnet(ctx context.Context, c *client.StorageClient, out *output, method string, value *big.Int) error {
+	chainID, err := c.ChainID(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if !client.IsMainnet(chainID) {
+		return nil
+	}
+	if out.json || !interactive() {
+		return fmt.Errorf("%w: chain %s", errConfirmationRequired, chainID)
 	}
-	fmt.Printf("Set transaction hash: %s\n", tx.Hash().Hex())
 
-	// Wait for the transaction to be mined (optional, but useful for demonstration).
-	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	cost := "an unknown amount"
+	if estimate, err := c.EstimateCost(ctx, method, value); err == nil {
+		cost = "up to " + client.FormatEther(estimate.MaxCost) + " ether"
+	}
+	prompt := fmt.Sprintf("Chain %s is a mainnet.  Send %s(%s) to %s, costing %s?",
+		chainID, method, value, c.Address().Hex(), cost)
+	ok, err := confirm(prompt)
 	if err != nil {
-		log.Fatalf("Transaction %s mining failed: %v", tx.Hash().Hex(), err)
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s cancelled", method)
 	}
+	return nil
+}
 
-	if receipt.Status == types.ReceiptStatusFailed {
-		log.Fatalf("Transaction %s failed", tx.Hash().Hex())
+// printTxResult reports the outcome of a set or add transaction.
+func printTxResult(method string, result *client.TxResult) {
+	if result.Skipped {
+		fmt.Printf("Value is already %s, %s skipped\n", result.NewValue, method)
+		return
 	}
-	fmt.Printf("Transaction mined in block %d\n", receipt.BlockNumber.Uint64())
+	if result.DryRun {
+		fmt.Printf("Dry run: %s would use %d gas and store %s\n", method, result.EstimatedGas, result.NewValue)
+		return
+	}
+	if result.Pending {
+		fmt.Printf("%s transaction %s submitted, check it with: simple-storage status %s\n",
+			method, result.TxHash.Hex(), result.TxHash.Hex())
+		return
+	}
+	fmt.Printf("%s transaction %s mined in block %d (gas used %d)\n",
+		method, result.TxHash.Hex(), result.BlockNumber, result.GasUsed)
+}
 
-	// 6. Get the updated value.
-	updatedValue, err := instance.Get(nil)
+// printMiningTimeout reports a transaction that was not mined in time.
+// That is not a failure, it may still be mined, so its hash is printed
+// for the status command.
+func printMiningTimeout(out *output, method string, result *client.TxResult) {
+	out.print(newTxOutput(result), func() {
+		fmt.Printf("%s transaction %s not mined yet, it may still be, check it with: simple-storage status %s\n",
+			method, result.TxHash.Hex(), result.TxHash.Hex())
+	})
+}
+
+// runCall calls a read-only contract method by name with arguments
+// parsed according to the ABI, and prints its outputs one per line.
+func runCall(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: simple-storage call <method> [args...]")
+	}
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	fmt.Println("Updated value:", updatedValue)
+	defer storageClient.Close()
 
-	// 7. Call the add function
-	addValue := big.NewInt(10)
-	authAdd, err := getTransactionAuthorizer(client)
+	method := args[0]
+	callArgs, err := storageClient.ParseArgs(method, args[1:])
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	gasAdd, err := instance.EstimateGas(&bind.CallOpts{From: authAdd.From, Context: context.Background()}, "add", addValue)
+	results, err := storageClient.Call(ctx, method, callArgs...)
 	if err != nil {
-		log.Fatal("Error estimating gas for add:", err)
+		return err
+	}
+	formatted := make([]string, len(results))
+	for i, r := range results {
+		formatted[i] = formatResult(r)
+	}
+	out.print(callOutput{Method: method, Results: formatted}, func() {
+		for _, r := range formatted {
+			fmt.Println(r)
+		}
+	})
+	return nil
+}
+
+// runGenericTransact sends a transaction calling a contract method by
+// name with arguments parsed according to the ABI.
+func runGenericTransact(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("transact", flag.ContinueOnError)
+	wei := fs.String("value", "", "native currency to send with the transaction, in wei")
+	noWait := fs.Bool("no-wait", cfg.NoWait, "print the transaction hash as soon as it is submitted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: simple-storage transact [-value wei] [-no-wait] <method> [args...]")
+	}
+	if *wei != "" {
+		amount, err := client.ParseValue(*wei)
+		if err != nil {
+			return fmt.Errorf("parsing -value: %w", err)
+		}
+		ctx = client.WithTxValue(ctx, amount)
 	}
-	fmt.Println("Estimated gas for add:", gasAdd)
-	authAdd.GasLimit = gasAdd + 20000
+	cfg.NoWait = *noWait
 
-	txAdd, err := instance.Add(authAdd, addValue)
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	fmt.Printf("Add transaction hash: %s\n", txAdd.Hash().Hex())
-	receiptAdd, err := bind.WaitMined(context.Background(), client, txAdd)
+	defer storageClient.Close()
+
+	method := fs.Arg(0)
+	txArgs, err := storageClient.ParseArgs(method, fs.Args()[1:])
 	if err != nil {
-		log.Fatalf("Transaction %s mining failed: %v", txAdd.Hash().Hex(), err)
+		return err
+	}
+	if err := checkSender(ctx, storageClient, cfg, out); err != nil {
+		return err
+	}
+	result, err := storageClient.Transact(ctx, method, txArgs...)
+	if errors.Is(err, client.ErrMiningTimeout) {
+		printMiningTimeout(out, method, result)
+		return nil
 	}
-	if receiptAdd.Status == types.ReceiptStatusFailed {
-		log.Fatalf("Transaction %s failed", txAdd.Hash().Hex())
+	if err != nil {
+		return err
 	}
+	out.print(newTxOutput(result), func() { printTxResult(method, result) })
+	return nil
+}
 
-	newValueAfterAdd, err := instance.Get(nil)
+// runStatus reports on a transaction submitted earlier, for instance with
+// -no-wait.
+func runStatus(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: simple-storage status <txhash>")
+	}
+	hash, err := client.ParseHash(args[0])
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
 
-	fmt.Println("New Value After Add:", newValueAfterAdd)
+	status, err := storageClient.TxStatus(ctx, hash)
+	if err != nil {
+		return err
+	}
+	out.print(newStatusOutput(status), func() {
+		switch status.State {
+		case client.TxNotFound:
+			fmt.Printf("Transaction %s not found: it was dropped or never received\n", hash.Hex())
+		case client.TxPending:
+			fmt.Printf("Transaction %s is pending\n", hash.Hex())
+		case client.TxMined:
+			fmt.Printf("Transaction %s mined in block %d (gas used %d)\n", hash.Hex(), status.BlockNumber, status.GasUsed)
+		case client.TxFailed:
+			reason := status.RevertReason
+			if reason == "" {
+				reason = "unknown reason"
+			}
+			fmt.Printf("Transaction %s failed in block %d (gas used %d): %s\n", hash.Hex(), status.BlockNumber, status.GasUsed, reason)
+		}
+	})
+	return nil
 }
 
-// getTransactionAuthorizer creates a `bind.TransactOptions` struct
-// for signing and submitting transactions.  It reads the private key
-// from the environment.
-func getTransactionAuthorizer(client *jumboclient.Client) (*bind.TransactOptions, error) {
-	privateKeyHex := os.Getenv("PRIVATE_KEY") // The sender's private key
-	if privateKeyHex == "" {
-		return nil, fmt.Errorf("PRIVATE_KEY environment variable not set")
+// runCapabilities reports what the node was found to support when the
+// client connected.
+func runCapabilities(ctx context.Context, cfg client.Config, out *output) error {
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
 	}
+	defer storageClient.Close()
+
+	caps := storageClient.Capabilities()
+	out.print(capabilitiesOutput{GasEstimation: caps.GasEstimation}, func() {
+		if caps.GasEstimation {
+			fmt.Println("gas estimation: supported")
+		} else {
+			fmt.Println("gas estimation: not supported, transactions use DEFAULT_GAS_LIMIT")
+		}
+	})
+	return nil
+}
+
+// runPrintConfig prints each setting the configuration reads, its value
+// with secrets masked, and the layer it came from.  Settings left at their
+// default are listed too, so the output doubles as a list of what can be
+// set.
+func runPrintConfig(out *output, settings []client.Setting) error {
+	out.print(newConfigOutput(settings), func() {
+		for _, s := range settings {
+			if s.Source == client.SourceDefault {
+				fmt.Printf("%s (default)\n", s.Name)
+				continue
+			}
+			fmt.Printf("%s=%s (%s)\n", s.Name, s.Value, s.Source)
+		}
+	})
+	return nil
+}
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+// runEstimate prints what a set, add or sub transaction would cost
+// without sending it.
+func runEstimate(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("estimate", flag.ContinueOnError)
+	wei := fs.String("value", "", "native currency the transaction would send, in wei")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || (fs.Arg(0) != "set" && fs.Arg(0) != "add" && fs.Arg(0) != "sub") {
+		return fmt.Errorf("usage: simple-storage estimate [-value wei] set|add|sub <value>")
+	}
+	method := fs.Arg(0)
+	value, err := client.ParseValue(fs.Arg(1))
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if *wei != "" {
+		amount, err := client.ParseValue(*wei)
+		if err != nil {
+			return fmt.Errorf("parsing -value: %w", err)
+		}
+		ctx = client.WithTxValue(ctx, amount)
 	}
 
-	// Get the sender's address from the private key.
-	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
 
-	// Get the nonce for the sender's address.
-	nonce, err := client.PendingNonceAt(context.Background(), address)
+	estimate, err := storageClient.EstimateCost(ctx, method, value)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	out.print(newEstimateOutput(method, estimate), func() {
+		fmt.Printf("%s would use about %d gas (limit %d) at up to %s gwei per gas\n",
+			method, estimate.Gas, estimate.GasLimit, client.FormatGwei(estimate.MaxFeePerGas))
+		fmt.Printf("Maximum cost: %s ETH (%s gwei)\n",
+			client.FormatEther(estimate.MaxCost), client.FormatGwei(estimate.MaxCost))
+	})
+	return nil
+}
 
-	// Chain ID is needed for EIP-155 signing.  Get it from the client.
-	chainID, err := client.ChainID(context.Background())
+// runSign signs a set, add or sub transaction without contacting the node
+// and prints it hex encoded for broadcast.
+func runSign(cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ContinueOnError)
+	chainID := fs.Uint64("chain-id", 0, "chain to sign for (default CHAIN_ID)")
+	nonce := fs.Uint64("nonce", 0, "sender nonce")
+	gasPrice := fs.String("gas-price", "", "gas price in gwei (default GAS_PRICE)")
+	gasLimit := fs.Uint64("gas-limit", 100000, "gas limit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || (fs.Arg(0) != "set" && fs.Arg(0) != "add" && fs.Arg(0) != "sub") {
+		return fmt.Errorf("usage: simple-storage sign [flags] set|add|sub <value>")
+	}
+	method := fs.Arg(0)
+	value, err := client.ParseValue(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	price := cfg.GasPrice
+	if *gasPrice != "" {
+		var err error
+		if price, err = client.ParseGwei(*gasPrice); err != nil {
+			return fmt.Errorf("invalid -gas-price: %w", err)
+		}
+	}
+	if price == nil {
+		return fmt.Errorf("gas price not set: pass -gas-price or set GAS_PRICE")
+	}
+	id := cfg.ChainID
+	if *chainID != 0 {
+		id = new(big.Int).SetUint64(*chainID)
+	}
+	if id == nil {
+		return fmt.Errorf("chain id not set: pass -chain-id or set CHAIN_ID")
+	}
+
+	signer, err := client.NewOfflineSigner(cfg)
+	if err != nil {
+		return err
+	}
+	defer signer.Close()
+
+	tx, raw, err := signer.BuildSignedTx(id, *nonce, price, *gasLimit, method, value)
+	if err != nil {
+		return err
+	}
+	out.print(signOutput{TxHash: tx.Hash().Hex(), RawTx: raw}, func() { fmt.Println(raw) })
+	return nil
+}
+
+// runBroadcast submits a transaction produced by the sign command.
+func runBroadcast(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: simple-storage broadcast <raw transaction>")
+	}
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	result, err := storageClient.Broadcast(ctx, args[0])
+	if errors.Is(err, client.ErrMiningTimeout) {
+		printMiningTimeout(out, "broadcast", result)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	out.print(newTxOutput(result), func() { printTxResult("broadcast", result) })
+	return nil
+}
+
+// runHistory prints the transactions recorded in the history file,
+// optionally filtered.
+func runHistory(out *output, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	path := fs.String("file", os.Getenv("TX_HISTORY_FILE"), "history file to read (default TX_HISTORY_FILE)")
+	method := fs.String("method", "", "only show transactions calling this method")
+	status := fs.String("status", "", "only show transactions with this status (success, reverted, error)")
+	since := fs.Duration("since", 0, "only show transactions from this long ago onwards")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return fmt.Errorf("no history file: pass -file or set TX_HISTORY_FILE")
+	}
+	filter := history.Filter{Method: *method, Status: *status}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	records, err := history.ReadFile(*path, filter)
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+	for _, rec := range records {
+		out.print(rec, func() {
+			fmt.Printf("%s %s(%s) %s %s block %d gas %d\n",
+				rec.Time.Format(time.RFC3339), rec.Method, strings.Join(rec.Args, ", "),
+				rec.TxHash, rec.Status, rec.BlockNumber, rec.GasUsed)
+		})
+	}
+	return nil
+}
+
+// runDeploy deploys a fresh contract and prints its address.
+func runDeploy(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("deploy", flag.ContinueOnError)
+	initVal := fs.String("init", "5", "initial stored value")
+	fs.StringVar(&cfg.ContractBin, "bin", cfg.ContractBin, "path to the compiled contract bytecode")
+	estimateOnly := fs.Bool("estimate-only", false, "print what the deployment would cost instead of deploying")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	value, err := client.ParseValue(*initVal)
+	if err != nil {
+		return err
+	}
+	if *estimateOnly {
+		estimate, err := client.EstimateDeployment(ctx, cfg, value)
+		if err != nil {
+			return err
+		}
+		out.print(newEstimateOutput("deploy", estimate), func() {
+			fmt.Printf("Deployment would use about %d gas at up to %s gwei per gas\n",
+				estimate.Gas, client.FormatGwei(estimate.MaxFeePerGas))
+			fmt.Printf("Maximum cost: %s ETH (%s gwei)\n",
+				client.FormatEther(estimate.MaxCost), client.FormatGwei(estimate.MaxCost))
+		})
+		return nil
+	}
+	storageClient, err := client.Deploy(ctx, cfg, value)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	address := storageClient.Address()
+	out.print(deployOutput{Address: address.Hex()}, func() {
+		fmt.Println("Contract deployed at:", address)
+		fmt.Printf("Add CONTRACT_ADDRESS=%s to your .env file\n", address.Hex())
+	})
+	return nil
+}
+
+// runWatch prints every ValueChanged event as it arrives.  WS_URL, or
+// RPC_URL if it is unset, must point at a websocket endpoint.  It runs
+// until interrupted, or until -duration has passed or -max-events events
+// have been printed.  Either way the subscription is closed before it
+// returns.
+func runWatch(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var setters setterList
+	fs.Var(&setters, "by", "only show changes made by this `address`; repeatable")
+	duration := fs.Duration("duration", 0, "stop watching after this long (default unlimited)")
+	maxEvents := fs.Int("max-events", 0, "stop watching after this many events (default unlimited)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *duration < 0 || *maxEvents < 0 {
+		return fmt.Errorf("-duration and -max-events must not be negative")
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if *duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+	events := make(chan *client.ValueChanged)
+	errc := make(chan error, 1)
+	go func() { errc <- storageClient.WatchValueChanged(ctx, events, setters...) }()
+
+	if !out.json {
+		fmt.Println("Watching", storageClient.Address(), "for value changes")
+	}
+	seen := 0
+	for {
+		select {
+		case ev := <-events:
+			out.print(newEventOutput(ev), func() { printValueChanged(ev) })
+			if seen++; seen == *maxEvents {
+				// Wait for the watcher to unsubscribe.
+				cancel()
+				<-errc
+				return nil
+			}
+		case err := <-errc:
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// runEvents prints the ValueChanged events in a block range, as each
+// chunk of blocks is read.
+func runEvents(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	from := fs.Uint64("from", 0, "first block to search")
+	to := fs.String("to", "latest", `last block to search, or "latest"`)
+	var setters setterList
+	fs.Var(&setters, "by", "only show changes made by this `address`; repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var toBlock *uint64
+	if *to != "latest" {
+		n, err := strconv.ParseUint(*to, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid -to block %q", *to)
+		}
+		toBlock = &n
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	// Print events as they are read rather than after the whole range.
+	events := make(chan *client.ValueChanged)
+	errc := make(chan error, 1)
+	go func() { errc <- storageClient.StreamFilterValueChanged(ctx, *from, toBlock, events, setters...) }()
+	count := 0
+	for ev := range events {
+		out.print(newEventOutput(ev), func() { printValueChanged(ev) })
+		count++
+	}
+	if err := <-errc; err != nil {
+		return err
+	}
+	if !out.json {
+		fmt.Printf("%d events\n", count)
+	}
+	return nil
+}
+
+// setterList collects the addresses given with repeated -by flags.
+type setterList []common.Address
+
+func (l *setterList) String() string {
+	parts := make([]string, len(*l))
+	for i, a := range *l {
+		parts[i] = a.Hex()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *setterList) Set(s string) error {
+	if !common.IsHexAddress(s) {
+		return fmt.Errorf("invalid address %q", s)
+	}
+	*l = append(*l, common.HexToAddress(s))
+	return nil
+}
+
+// envOr returns the environment variable key, or def if it is unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func printValueChanged(ev *client.ValueChanged) {
+	if ev.Removed {
+		fmt.Printf("Block %d: reorged out, undo %s -> %s (tx %s)\n",
+			ev.BlockNumber, ev.OldValue, ev.NewValue, ev.TxHash.Hex())
+		return
+	}
+	fmt.Printf("Block %d: %s -> %s (set by %s, tx %s)\n",
+		ev.BlockNumber, ev.OldValue, ev.NewValue, ev.Setter.Hex(), ev.TxHash.Hex())
+}
+
+// runDemo walks through reading, setting and adding to the stored value.
+// In JSON mode the steps are reported together once the walk finishes.
+func runDemo(ctx context.Context, cfg client.Config, out *output) error {
+	// 1. Connect to the node and bind the contract.  The contract is
+	//    assumed to be already deployed; see the deploy subcommand.  Each
+	//    step reads what the one before it stored, so always wait.
+	cfg.NoWait = false
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+	human := !out.json
+	if human {
+		fmt.Println("Contract Address:", storageClient.Address())
+	}
+
+	// 2. Get the initial value.
+	initialValue, err := storageClient.Get(ctx)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if human {
+		fmt.Println("Initial value:", initialValue)
+	}
+
+	// 3. Set a new value and wait for it to be mined.
+	result, err := storageClient.Set(ctx, big.NewInt(150))
+	if err != nil {
+		return err
+	}
+	if human {
+		if result.DryRun {
+			fmt.Printf("Dry run: set would use %d gas and store %s\n", result.EstimatedGas, result.NewValue)
+		} else {
+			fmt.Printf("Set transaction hash: %s\n", result.TxHash.Hex())
+			fmt.Printf("Transaction mined in block %d (gas used %d)\n", result.BlockNumber, result.GasUsed)
+		}
+	}
+
+	// 4. Get the updated value.
+	updatedValue, err := storageClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if human {
+		fmt.Println("Updated value:", updatedValue)
 	}
 
-	// Create a new `bind.TransactOptions` struct.  This struct holds
-	// all the necessary information for signing and sending a transaction.
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	// 5. Call the add function.
+	resultAdd, err := storageClient.Add(ctx, big.NewInt(10))
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if human {
+		fmt.Printf("Add transaction hash: %s\n", resultAdd.TxHash.Hex())
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // Amount to send (in wei).  Set to 0 for contract calls.
-	auth.GasLimit = uint64(3000000) //  Maximum gas allowed for the transaction.
-	// GasPrice is set automatically by ethclient in newer versions, but
-	// you might need to set it manually for older versions or for more
-	// control.  We'll leave it commented out for now and let geth handle it.
-	// auth.GasPrice = gasPrice
 
-	return auth, nil
+	newValueAfterAdd, err := storageClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	out.print(demoOutput{
+		InitialValue: initialValue.String(),
+		Set:          newTxOutput(result),
+		UpdatedValue: updatedValue.String(),
+		Add:          newTxOutput(resultAdd),
+		FinalValue:   newValueAfterAdd.String(),
+	}, func() { fmt.Println("New Value After Add:", newValueAfterAdd) })
+	return nil
 }
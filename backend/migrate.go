@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+)
+
+// migrationOutput reports a value copied from an old contract.
+type migrationOutput struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Value string   `json:"value"`
+	Tx    txOutput `json:"tx"`
+}
+
+// runMigrate copies the value of the contract at an old address into the
+// configured one and checks it arrived.  The old contract is read over
+// RPC_URL, or over -from-rpc if it lives on another chain, in which case
+// the copy has to be confirmed.
+func runMigrate(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fromRPC := fs.String("from-rpc", "", "rpc url of the old contract's node, if it is on another chain (default RPC_URL)")
+	yes := fs.Bool("yes", false, "migrate across chains or on a mainnet without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simple-storage migrate [-from-rpc url] [-yes] <old-address>")
+	}
+	old, err := client.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// The old contract is only read.
+	srcCfg := cfg
+	srcCfg.ContractAddress = old.Hex()
+	srcCfg.AutoDeploy = false
+	srcCfg.ReadOnly = true
+	if *fromRPC != "" {
+		srcCfg.RPCURL = *fromRPC
+		srcCfg.WSURL = ""
+		srcCfg.ChainID = nil
+		srcCfg.ExpectedChainID = nil
+	}
+	src, err := client.NewStorageClientFromConfig(ctx, srcCfg)
+	if err != nil {
+		return fmt.Errorf("old contract: %w", err)
+	}
+	defer src.Close()
+
+	cfg.NoWait = false
+	dst, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	srcChain, err := src.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	dstChain, err := dst.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	crossChain := srcChain.Cmp(dstChain) != 0
+	if !*yes && !cfg.DryRun {
+		if crossChain {
+			if out.json || !interactive() {
+				return fmt.Errorf("%w: migrating from chain %s to chain %s", errConfirmationRequired, srcChain, dstChain)
+			}
+			prompt := fmt.Sprintf("%s is on chain %s but %s is on chain %s.  Copy its value across chains?",
+				old.Hex(), srcChain, dst.Address().Hex(), dstChain)
+			ok, err := confirm(prompt)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("migration cancelled")
+			}
+		}
+		value, err := src.Get(ctx)
+		if err != nil {
+			return err
+		}
+		if err := confirmMainnet(ctx, dst, out, "set", value); err != nil {
+			return err
+		}
+	}
+
+	migration, err := client.Migrate(ctx, src, dst, crossChain)
+	if err != nil {
+		return err
+	}
+	out.print(migrationOutput{
+		From:  old.Hex(),
+		To:    dst.Address().Hex(),
+		Value: migration.Value.String(),
+		Tx:    newTxOutput(migration.Result),
+	}, func() {
+		fmt.Printf("Copied value %s from %s to %s\n", migration.Value, old.Hex(), dst.Address().Hex())
+		printTxResult("set", migration.Result)
+	})
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/jumbochain/jumbochain-go/accounts/keystore"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+	"github.com/jumbochain/jumbochain-go/crypto"
+)
+
+// newAccountOutput describes a generated account.  PrivateKey is only set
+// with -print-key.
+type newAccountOutput struct {
+	Address      string `json:"address"`
+	KeystoreFile string `json:"keystoreFile,omitempty"`
+	PrivateKey   string `json:"privateKey,omitempty"`
+}
+
+// runNewAccount generates a key and prints its address, for getting
+// started without other tools.  The key is kept only if asked: encrypted
+// into a keystore directory with -keystore, under KEYSTORE_PASSWORD or a
+// password typed at the prompt, or printed to stdout with -print-key.  It
+// is never written to a file unencrypted.
+func runNewAccount(cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("new-account", flag.ContinueOnError)
+	dir := fs.String("keystore", "", "directory to write the key to as an encrypted keystore file")
+	printKey := fs.Bool("print-key", false, "print the unencrypted private key to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: simple-storage new-account [-keystore dir] [-print-key]")
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+	result := newAccountOutput{Address: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+
+	if *dir != "" {
+		password := cfg.KeystorePassword
+		if password == "" {
+			if password, err = promptNewPassword(); err != nil {
+				return err
+			}
+		}
+		account, err := keystore.NewKeyStore(*dir, keystore.StandardScryptN, keystore.StandardScryptP).ImportECDSA(key, password)
+		if err != nil {
+			return fmt.Errorf("writing keystore: %w", err)
+		}
+		result.KeystoreFile = account.URL.Path
+	}
+	if *printKey {
+		fmt.Fprintln(os.Stderr, "WARNING: the private key below controls the account's funds.  Anyone who sees it can spend them; do not share it, and do not use this account on a mainnet.")
+		result.PrivateKey = strings.TrimPrefix(hexutil.Encode(crypto.FromECDSA(key)), "0x")
+	}
+
+	out.print(result, func() {
+		fmt.Println("Address:", result.Address)
+		switch {
+		case result.KeystoreFile != "":
+			fmt.Println("Keystore:", result.KeystoreFile)
+		case result.PrivateKey == "":
+			fmt.Fprintln(os.Stderr, "The key was not saved; pass -keystore dir or -print-key to keep it.")
+		}
+		if result.PrivateKey != "" {
+			fmt.Println("Private key:", result.PrivateKey)
+		}
+	})
+	return nil
+}
+
+// promptNewPassword asks twice for the password to encrypt a new keystore
+// with, hiding the input if the terminal allows.
+func promptNewPassword() (string, error) {
+	if !interactive() {
+		return "", fmt.Errorf("no terminal to ask for a keystore password on, set KEYSTORE_PASSWORD")
+	}
+	in := bufio.NewReader(os.Stdin)
+	password, err := readPassword(in, "Keystore password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("keystore password must not be empty")
+	}
+	again, err := readPassword(in, "Repeat password: ")
+	if err != nil {
+		return "", err
+	}
+	if again != password {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return password, nil
+}
+
+// readPassword asks prompt on stderr and reads a line from in with echo
+// turned off through stty.  Where stty is missing the input is echoed.
+func readPassword(in *bufio.Reader, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if stty("-echo") == nil {
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stty applies setting to the terminal on stdin.
+func stty(setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+)
+
+// output writes command results either as human-readable text or, with
+// -json, as one JSON object per result on stdout.
+type output struct {
+	json bool
+}
+
+// print writes v as JSON in JSON mode and calls human otherwise.
+func (o *output) print(v any, human func()) {
+	if !o.json {
+		human()
+		return
+	}
+	// Encoding these plain structs cannot fail.
+	_ = json.NewEncoder(os.Stdout).Encode(v)
+}
+
+// error reports a failed command on stderr, as JSON in JSON mode.
+func (o *output) error(logger *slog.Logger, err error) {
+	if !o.json {
+		logger.Error("command failed", "err", err)
+		return
+	}
+	_ = json.NewEncoder(os.Stderr).Encode(errorOutput{Error: err.Error(), Code: errorCode(err)})
+}
+
+// errorCode classifies err for scripts that want to branch on the kind of
+// failure without matching message text.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return "usage"
+	case errors.Is(err, errConfirmationRequired):
+		return "confirmation_required"
+	case errors.Is(err, client.ErrStateUnavailable):
+		return "state_unavailable"
+	case errors.Is(err, client.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, client.ErrMiningTimeout):
+		return "mining_timeout"
+	case errors.Is(err, client.ErrTimeout):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, client.ErrContractNotDeployed):
+		return "no_contract"
+	case errors.Is(err, client.ErrNoSubscriptions):
+		return "no_subscriptions"
+	case errors.Is(err, client.ErrBytecodeMismatch):
+		return "bytecode_mismatch"
+	case errors.Is(err, client.ErrAutoDeployRefused):
+		return "auto_deploy_refused"
+	case errors.Is(err, client.ErrNotReplayProtected):
+		return "not_replay_protected"
+	case errors.Is(err, client.ErrIdempotencyConflict):
+		return "idempotency_conflict"
+	case errors.Is(err, client.ErrSignerTimeout):
+		return "signer_timeout"
+	case errors.Is(err, client.ErrNoHardwareWallet):
+		return "no_hardware_wallet"
+	case errors.Is(err, client.ErrAccountNotFound):
+		return "account_not_found"
+	case errors.Is(err, client.ErrNoSigningKey):
+		return "no_signing_key"
+	case errors.Is(err, client.ErrInvalidKey):
+		return "invalid_key"
+	case errors.Is(err, client.ErrReadOnly):
+		return "read_only"
+	case errors.Is(err, client.ErrSignerMismatch):
+		return "signer_mismatch"
+	case errors.Is(err, client.ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, client.ErrInvalidTypedData):
+		return "invalid_typed_data"
+	case errors.Is(err, client.ErrGasEstimationUnsupported):
+		return "gas_estimation_unsupported"
+	case errors.Is(err, client.ErrTxTypeUnsupported):
+		return "tx_type_unsupported"
+	case errors.Is(err, client.ErrAccessListUnsupported):
+		return "access_list_unsupported"
+	case errors.Is(err, client.ErrRelayDisabled):
+		return "relay_disabled"
+	case errors.Is(err, client.ErrRelaySignature):
+		return "bad_relay_signature"
+	case errors.Is(err, client.ErrRelayNonce):
+		return "relay_nonce"
+	case errors.Is(err, client.ErrCrossChain):
+		return "cross_chain"
+	case errors.Is(err, client.ErrMigrationMismatch):
+		return "migration_mismatch"
+	case errors.Is(err, client.ErrValueOutOfRange):
+		return "out_of_range"
+	case errors.Is(err, client.ErrUnderflow):
+		return "underflow"
+	case errors.Is(err, client.ErrNotAuthorized):
+		return "not_authorized"
+	case errors.Is(err, client.ErrReverted):
+		return "reverted"
+	case errors.Is(err, client.ErrNonceTooHigh):
+		return "nonce_too_high"
+	case errors.Is(err, client.ErrNonceTooLow):
+		return "nonce_too_low"
+	case errors.Is(err, client.ErrInsufficientFunds):
+		return "insufficient_funds"
+	case errors.Is(err, client.ErrChainMismatch):
+		return "wrong_chain"
+	case errors.Is(err, client.ErrReorged):
+		return "reorged"
+	default:
+		return "error"
+	}
+}
+
+type errorOutput struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+type valueOutput struct {
+	Value string `json:"value"`
+
+	// Initialized is set by get -initialized.
+	Initialized *bool `json:"initialized,omitempty"`
+}
+
+type txOutput struct {
+	TxHash       string `json:"txHash,omitempty"`
+	BlockNumber  uint64 `json:"blockNumber,omitempty"`
+	GasUsed      uint64 `json:"gasUsed,omitempty"`
+	DryRun       bool   `json:"dryRun,omitempty"`
+	EstimatedGas uint64 `json:"estimatedGas,omitempty"`
+	NewValue     string `json:"newValue,omitempty"`
+	Skipped      bool   `json:"skipped,omitempty"`
+	Pending      bool   `json:"pending,omitempty"`
+}
+
+func newTxOutput(result *client.TxResult) txOutput {
+	if result.Skipped {
+		return txOutput{Skipped: true, NewValue: result.NewValue.String()}
+	}
+	if result.DryRun {
+		return txOutput{DryRun: true, EstimatedGas: result.EstimatedGas, NewValue: result.NewValue.String()}
+	}
+	if result.Pending {
+		return txOutput{TxHash: result.TxHash.Hex(), Pending: true}
+	}
+	return txOutput{TxHash: result.TxHash.Hex(), BlockNumber: result.BlockNumber, GasUsed: result.GasUsed}
+}
+
+type estimateOutput struct {
+	Method          string `json:"method"`
+	Gas             uint64 `json:"gas"`
+	GasLimit        uint64 `json:"gasLimit"`
+	MaxFeePerGasWei string `json:"maxFeePerGasWei"`
+	ValueWei        string `json:"valueWei"`
+	MaxCostWei      string `json:"maxCostWei"`
+	MaxCostGwei     string `json:"maxCostGwei"`
+	MaxCostEther    string `json:"maxCostEther"`
+}
+
+func newEstimateOutput(method string, estimate *client.CostEstimate) estimateOutput {
+	return estimateOutput{
+		Method:          method,
+		Gas:             estimate.Gas,
+		GasLimit:        estimate.GasLimit,
+		MaxFeePerGasWei: estimate.MaxFeePerGas.String(),
+		ValueWei:        estimate.Value.String(),
+		MaxCostWei:      estimate.MaxCost.String(),
+		MaxCostGwei:     client.FormatGwei(estimate.MaxCost),
+		MaxCostEther:    client.FormatEther(estimate.MaxCost),
+	}
+}
+
+type statusOutput struct {
+	TxHash       string `json:"txHash"`
+	Status       string `json:"status"`
+	BlockNumber  uint64 `json:"blockNumber,omitempty"`
+	GasUsed      uint64 `json:"gasUsed,omitempty"`
+	RevertReason string `json:"revertReason,omitempty"`
+}
+
+func newStatusOutput(status *client.TxStatus) statusOutput {
+	return statusOutput{
+		TxHash:       status.TxHash.Hex(),
+		Status:       string(status.State),
+		BlockNumber:  status.BlockNumber,
+		GasUsed:      status.GasUsed,
+		RevertReason: status.RevertReason,
+	}
+}
+
+type settingOutput struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source"`
+}
+
+func newConfigOutput(settings []client.Setting) []settingOutput {
+	out := make([]settingOutput, len(settings))
+	for i, s := range settings {
+		out[i] = settingOutput{Name: s.Name, Value: s.Value, Source: string(s.Source)}
+	}
+	return out
+}
+
+type signOutput struct {
+	TxHash string `json:"txHash"`
+	RawTx  string `json:"rawTx"`
+}
+
+type capabilitiesOutput struct {
+	GasEstimation bool `json:"gasEstimation"`
+}
+
+type relayNonceOutput struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+type callOutput struct {
+	Method  string   `json:"method"`
+	Results []string `json:"results"`
+}
+
+type deployOutput struct {
+	Address string `json:"address"`
+}
+
+type eventOutput struct {
+	Setter      string `json:"setter"`
+	OldValue    string `json:"oldValue"`
+	NewValue    string `json:"newValue"`
+	BlockNumber uint64 `json:"blockNumber"`
+	TxHash      string `json:"txHash"`
+	LogIndex    uint   `json:"logIndex"`
+	Removed     bool   `json:"removed,omitempty"`
+}
+
+func newEventOutput(ev *client.ValueChanged) eventOutput {
+	return eventOutput{
+		Setter:      ev.Setter.Hex(),
+		OldValue:    ev.OldValue.String(),
+		NewValue:    ev.NewValue.String(),
+		BlockNumber: ev.BlockNumber,
+		TxHash:      ev.TxHash.Hex(),
+		LogIndex:    ev.LogIndex,
+		Removed:     ev.Removed,
+	}
+}
+
+type demoOutput struct {
+	InitialValue string   `json:"initialValue"`
+	Set          txOutput `json:"set"`
+	UpdatedValue string   `json:"updatedValue"`
+	Add          txOutput `json:"add"`
+	FinalValue   string   `json:"finalValue"`
+}
+
+// formatResult renders a value returned by Call for printing.  Bytes are
+// shown in hex.
+func formatResult(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	}
+	if rv.Kind() == reflect.Slice {
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = formatResult(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
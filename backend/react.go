@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/jumbochain/jumbochain-go/common"
+)
+
+// defaultReactCooldown is the least time between two reactions.
+const defaultReactCooldown = 30 * time.Second
+
+// reactionOutput reports a transaction react sent in response to an event.
+type reactionOutput struct {
+	Trigger string   `json:"trigger"`
+	Value   string   `json:"value"`
+	Action  string   `json:"action"`
+	Amount  string   `json:"amount"`
+	Tx      txOutput `json:"tx"`
+}
+
+// runReact watches ValueChanged events and brings the stored value back
+// into [-min, -max] whenever another account moves it out: with an add
+// when it falls below min and a set when it rises above max.  Events from
+// its own transactions are ignored, and it reacts at most once per
+// -cooldown.
+func runReact(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("react", flag.ContinueOnError)
+	minFlag := fs.String("min", "", "add up to this value when the stored value falls below it")
+	maxFlag := fs.String("max", "", "set this value when the stored value rises above it")
+	cooldown := fs.Duration("cooldown", defaultReactCooldown, "least time between two reactions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || (*minFlag == "" && *maxFlag == "") {
+		return fmt.Errorf("usage: simple-storage react [-min v] [-max v] [-cooldown d]")
+	}
+	var lo, hi *big.Int
+	var err error
+	if *minFlag != "" {
+		if lo, err = client.ParseValue(*minFlag); err != nil {
+			return fmt.Errorf("parsing -min: %w", err)
+		}
+	}
+	if *maxFlag != "" {
+		if hi, err = client.ParseValue(*maxFlag); err != nil {
+			return fmt.Errorf("parsing -max: %w", err)
+		}
+	}
+	if lo != nil && hi != nil && lo.Cmp(hi) > 0 {
+		return fmt.Errorf("-min %s is above -max %s", lo, hi)
+	}
+
+	// Reactions are reported once submitted, so the watch loop is never
+	// blocked waiting for one to be mined.
+	cfg.NoWait = true
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan *client.ValueChanged)
+	errc := make(chan error, 1)
+	go func() { errc <- storageClient.WatchValueChanged(ctx, events) }()
+
+	if !out.json {
+		fmt.Println("Watching", storageClient.Address(), "to keep its value in range")
+	}
+	own := make(map[common.Hash]bool) // reactions not yet seen as events
+	var last time.Time
+	for {
+		var ev *client.ValueChanged
+		select {
+		case ev = <-events:
+		case err := <-errc:
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+
+		// Removals carry no new value to keep in range; the chain that
+		// replaced them delivers its own events.
+		if ev.Removed {
+			continue
+		}
+		if own[ev.TxHash] {
+			delete(own, ev.TxHash)
+			continue
+		}
+		var action string
+		var amount *big.Int
+		var send func(context.Context, *big.Int) (*client.TxResult, error)
+		switch {
+		case lo != nil && ev.NewValue.Cmp(lo) < 0:
+			action, amount, send = "add", new(big.Int).Sub(lo, ev.NewValue), storageClient.Add
+		case hi != nil && ev.NewValue.Cmp(hi) > 0:
+			action, amount, send = "set", hi, storageClient.Set
+		default:
+			continue
+		}
+		if wait := *cooldown - time.Since(last); wait > 0 {
+			cfg.Logger.WarnContext(ctx, "value out of range, not reacting during cooldown",
+				"value", ev.NewValue, "tx", ev.TxHash, "cooldownLeft", wait.Round(time.Second))
+			continue
+		}
+
+		last = time.Now()
+		result, err := send(ctx, amount)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			cfg.Logger.ErrorContext(ctx, "reaction failed", "action", action, "amount", amount, "tx", ev.TxHash, "err", err)
+			continue
+		}
+		if result.TxHash != (common.Hash{}) {
+			own[result.TxHash] = true
+		}
+		out.print(reactionOutput{
+			Trigger: ev.TxHash.Hex(),
+			Value:   ev.NewValue.String(),
+			Action:  action,
+			Amount:  amount.String(),
+			Tx:      newTxOutput(result),
+		}, func() {
+			fmt.Printf("Value %s out of range after tx %s, sent %s %s\n", ev.NewValue, ev.TxHash.Hex(), action, amount)
+			printTxResult(action, result)
+		})
+	}
+}
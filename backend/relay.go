@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+)
+
+const relayUsage = "usage: simple-storage relay sign -nonce n set|add|sub <value> | relay send <file> | relay nonce <address>"
+
+// runRelay dispatches the relay subcommands: sign, run by a user to sign
+// a request with their key, and send and nonce, run by the relayer.
+func runRelay(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(relayUsage)
+	}
+	switch args[0] {
+	case "sign":
+		return runRelaySign(ctx, cfg, out, args[1:])
+	case "send":
+		return runRelaySend(ctx, cfg, out, args[1:])
+	case "nonce":
+		return runRelayNonce(ctx, cfg, out, args[1:])
+	default:
+		return fmt.Errorf(relayUsage)
+	}
+}
+
+// runRelaySign prints a relay request for a set, add or sub signed by the
+// configured account, for a relayer to send with relay send or POST
+// /relay.
+func runRelaySign(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("relay sign", flag.ContinueOnError)
+	nonce := fs.Int64("nonce", -1, "the account's next relay nonce, as reported by the relayer")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *nonce < 0 {
+		return fmt.Errorf("usage: simple-storage relay sign -nonce n set|add|sub <value>")
+	}
+	value, err := client.ParseValue(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	req, err := storageClient.SignRelay(ctx, fs.Arg(0), value, uint64(*nonce))
+	if err != nil {
+		return err
+	}
+	out.print(req, func() {
+		data, _ := json.MarshalIndent(req, "", "  ")
+		fmt.Println(string(data))
+	})
+	return nil
+}
+
+// runRelaySend relays the request in a file, or on standard input if the
+// file is "-", paying for it with the configured account.
+func runRelaySend(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: simple-storage relay send <file>")
+	}
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("reading relay request: %w", err)
+	}
+	var req client.RelayRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("parsing relay request: %w", err)
+	}
+
+	// Running relay send is consent enough; RELAY guards the server.
+	cfg.Relay = true
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	result, err := storageClient.Relay(ctx, &req)
+	if err != nil {
+		return err
+	}
+	out.print(newTxOutput(result), func() { printTxResult("relayed "+req.Method, result) })
+	return nil
+}
+
+// runRelayNonce prints the nonce a user's next relay request must carry.
+func runRelayNonce(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: simple-storage relay nonce <address>")
+	}
+	user, err := client.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	nonce, err := storageClient.RelayNonce(user)
+	if err != nil {
+		return err
+	}
+	out.print(relayNonceOutput{Address: user.Hex(), Nonce: nonce}, func() { fmt.Println(nonce) })
+	return nil
+}
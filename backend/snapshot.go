@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+)
+
+// snapshotFile is the JSON layout written by export and read by import.
+// Numbers that may exceed 2^53 are decimal strings.
+type snapshotFile struct {
+	ContractAddress string    `json:"contractAddress"`
+	ChainID         string    `json:"chainId"`
+	Value           string    `json:"value"`
+	BlockNumber     uint64    `json:"blockNumber"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// runExport writes a snapshot of the contract's state to a file.
+func runExport(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simple-storage export <file>")
+	}
+	path := fs.Arg(0)
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	snap, err := storageClient.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	file := snapshotFile{
+		ContractAddress: snap.Address.Hex(),
+		ChainID:         snap.ChainID.String(),
+		Value:           snap.Value.String(),
+		BlockNumber:     snap.BlockNumber,
+		Timestamp:       snap.Time,
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	out.print(file, func() {
+		fmt.Printf("Exported value %s at block %d to %s\n", file.Value, file.BlockNumber, path)
+	})
+	return nil
+}
+
+// runImport restores the value recorded in a snapshot file by calling set
+// on the configured contract, after asking for confirmation.
+func runImport(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "restore without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simple-storage import [-yes] <file>")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	var file snapshotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing snapshot: %w", err)
+	}
+	value, err := client.ParseValue(file.Value)
+	if err != nil {
+		return fmt.Errorf("snapshot value: %w", err)
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	if !*yes {
+		prompt := fmt.Sprintf("Set %s to %s, exported from %s on chain %s at block %d?",
+			storageClient.Address().Hex(), file.Value, file.ContractAddress, file.ChainID, file.BlockNumber)
+		ok, err := confirm(prompt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("import cancelled")
+		}
+	}
+
+	result, err := storageClient.Set(ctx, value)
+	if err != nil {
+		return err
+	}
+	out.print(newTxOutput(result), func() { printTxResult("set", result) })
+	return nil
+}
+
+// confirm asks prompt on stderr and reports whether the answer read from
+// stdin was yes.
+func confirm(prompt string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("reading confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// interactive reports whether stdin is a terminal, so a prompt can be
+// answered.
+func interactive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/history"
+)
+
+// gasStatsOutput summarises the gas used by the mined transactions that
+// called one method.
+type gasStatsOutput struct {
+	Method   string `json:"method"`
+	Mined    int    `json:"mined"`
+	Reverted int    `json:"reverted"`
+	TotalGas uint64 `json:"totalGas"`
+	MeanGas  uint64 `json:"meanGas"`
+	MinGas   uint64 `json:"minGas"`
+	MaxGas   uint64 `json:"maxGas"`
+}
+
+// runStats prints the gas used per method by the transactions recorded in
+// the history file.  Only mined transactions count; pending and failed
+// submissions used no gas the file knows of.
+func runStats(out *output, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	path := fs.String("file", os.Getenv("TX_HISTORY_FILE"), "history file to read (default TX_HISTORY_FILE)")
+	since := fs.Duration("since", 0, "only count transactions from this long ago onwards")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return fmt.Errorf("no history file: pass -file or set TX_HISTORY_FILE")
+	}
+	var filter history.Filter
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	records, err := history.ReadFile(*path, filter)
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+	byMethod := make(map[string]*gasStatsOutput)
+	for _, rec := range records {
+		if rec.Status != history.StatusSuccess && rec.Status != history.StatusReverted {
+			continue
+		}
+		s := byMethod[rec.Method]
+		if s == nil {
+			s = &gasStatsOutput{Method: rec.Method, MinGas: rec.GasUsed}
+			byMethod[rec.Method] = s
+		}
+		s.Mined++
+		if rec.Status == history.StatusReverted {
+			s.Reverted++
+		}
+		s.TotalGas += rec.GasUsed
+		s.MinGas = min(s.MinGas, rec.GasUsed)
+		s.MaxGas = max(s.MaxGas, rec.GasUsed)
+	}
+
+	methods := make([]string, 0, len(byMethod))
+	for method := range byMethod {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	if len(methods) == 0 && !out.json {
+		fmt.Println("No mined transactions recorded")
+	}
+	for _, method := range methods {
+		s := byMethod[method]
+		s.MeanGas = s.TotalGas / uint64(s.Mined)
+		out.print(s, func() {
+			fmt.Printf("%s: %d mined (%d reverted), gas total %d mean %d min %d max %d\n",
+				s.Method, s.Mined, s.Reverted, s.TotalGas, s.MeanGas, s.MinGas, s.MaxGas)
+		})
+	}
+	return nil
+}
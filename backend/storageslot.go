@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/digidny/simple-storage-dapp/backend/internal/client"
+	"github.com/jumbochain/jumbochain-go/common"
+	"github.com/jumbochain/jumbochain-go/common/hexutil"
+)
+
+// slotOutput is the raw contents of one storage slot of the contract.
+type slotOutput struct {
+	Slot     string `json:"slot"`
+	Contents string `json:"contents"`
+	Value    string `json:"value,omitempty"`
+}
+
+// runStorageSlot prints the raw contents of a storage slot of the
+// contract, bypassing the ABI, to check the getter against what is
+// actually stored.  The value is in slot 0.
+func runStorageSlot(ctx context.Context, cfg client.Config, out *output, args []string) error {
+	fs := flag.NewFlagSet("storage-slot", flag.ContinueOnError)
+	asInt := fs.Bool("int", false, "also print the contents as an unsigned integer")
+	block := fs.String("block", "latest", `block to read the slot at, or "latest"`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simple-storage storage-slot [-int] [-block n] <slot>")
+	}
+	// SetString with base 0 takes both decimal and 0x-prefixed hex.
+	n, ok := new(big.Int).SetString(fs.Arg(0), 0)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return fmt.Errorf("invalid slot %q: want a number from 0 to 2^256-1, in decimal or 0x hex", fs.Arg(0))
+	}
+	slot := common.BigToHash(n)
+	var atBlock *big.Int
+	if *block != "latest" {
+		b, err := strconv.ParseUint(*block, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid -block %q", *block)
+		}
+		atBlock = new(big.Int).SetUint64(b)
+	}
+
+	storageClient, err := client.NewStorageClientFromConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storageClient.Close()
+
+	contents, err := storageClient.StorageAt(ctx, slot, atBlock)
+	if err != nil {
+		return err
+	}
+	result := slotOutput{Slot: slot.Hex(), Contents: hexutil.Encode(common.LeftPadBytes(contents, 32))}
+	if *asInt {
+		result.Value = new(big.Int).SetBytes(contents).String()
+	}
+	out.print(result, func() {
+		fmt.Println(result.Contents)
+		if result.Value != "" {
+			fmt.Println(result.Value)
+		}
+	})
+	return nil
+}
@@ -4,11 +4,14 @@ pragma solidity ^0.8.0;
 contract SimpleStorage {
     uint256 storedData;
 
+    event ValueChanged(address indexed setter, uint256 oldValue, uint256 newValue);
+
     constructor(uint256 initVal) {
         storedData = initVal;
     }
 
     function set(uint256 x) public {
+        emit ValueChanged(msg.sender, storedData, x);
         storedData = x;
     }
 
@@ -17,7 +20,17 @@ contract SimpleStorage {
     }
 
      function add(uint256 x) public returns (uint256) {
+        uint256 oldValue = storedData;
         storedData = storedData + x;
+        emit ValueChanged(msg.sender, oldValue, storedData);
+        return storedData;
+    }
+
+    function sub(uint256 x) public returns (uint256) {
+        require(x <= storedData, "SimpleStorage: underflow");
+        uint256 oldValue = storedData;
+        storedData = storedData - x;
+        emit ValueChanged(msg.sender, oldValue, storedData);
         return storedData;
     }
 }